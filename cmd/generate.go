@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+	"github.com/Valery223/biathlon-test/internal/generator"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+)
+
+// runGenerate writes the events file of a synthetic race for the config.
+// The expected results can be written as well to check the report against them.
+func runGenerate(args []string) {
+	defaults := generator.DefaultParams()
+
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config file (.json, .yaml, .yml or .toml)")
+	competitors := fs.Int("competitors", defaults.Competitors, "number of competitors")
+	speed := fs.Float64("speed", defaults.SpeedMean, "mean skiing speed in m/s")
+	speedStdDev := fs.Float64("speed-stddev", defaults.SpeedStdDev, "standard deviation of the skiing speed in m/s")
+	hit := fs.Float64("hit", defaults.HitProbability, "probability to hit a target")
+	dns := fs.Float64("dns", defaults.DNSRate, "share of competitors who do not start")
+	dnf := fs.Float64("dnf", defaults.DNFRate, "share of competitors who do not finish")
+	seed := fs.Uint64("seed", defaults.Seed, "random seed, the same seed generates the same race")
+	outPath := fs.String("out", "", "path to write the events to, standard output by default")
+	expectedPath := fs.String("expected", "", "path to write the expected results in CSV format (optional)")
+	fs.Parse(args)
+
+	cfg := config.MustLoadConfig(*configPath)
+
+	race, err := generator.Generate(cfg, generator.Params{
+		Competitors:    *competitors,
+		SpeedMean:      *speed,
+		SpeedStdDev:    *speedStdDev,
+		HitProbability: *hit,
+		DNSRate:        *dns,
+		DNFRate:        *dnf,
+		Seed:           *seed,
+	})
+	if err != nil {
+		log.Fatalf("invalid parameters: %v", err)
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		out, err = os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("failed to create events file: %v", err)
+		}
+		defer out.Close()
+	}
+	if err := writeEvents(out, race.Events); err != nil {
+		log.Fatalf("failed to write events: %v", err)
+	}
+
+	if *expectedPath != "" {
+		f, err := os.Create(*expectedPath)
+		if err != nil {
+			log.Fatalf("failed to create expected results file: %v", err)
+		}
+		defer f.Close()
+		if err := generator.WriteExpected(f, race.Expected); err != nil {
+			log.Fatalf("failed to write expected results: %v", err)
+		}
+	}
+}
+
+// writeEvents writes the events in the events file format.
+func writeEvents(w io.Writer, events []domain.Event) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range events {
+		if _, err := fmt.Fprintln(bw, scannerEvent.FormatLine(e)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -1,40 +1,206 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/registry"
+	"github.com/Valery223/biathlon-test/internal/reporting"
 	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+	"github.com/Valery223/biathlon-test/internal/sink"
 	"github.com/Valery223/biathlon-test/internal/task"
 )
 
 const defaultConfigPath = "sunny_5_skiers/config.json"
 const defaultEventPath = "sunny_5_skiers/events"
+const followPollInterval = 200 * time.Millisecond
+
+// commands maps subcommand names to their entry points.
+// Without a subcommand the events file is processed and the final report is printed.
+var commands = map[string]func(args []string){
+	"serve":    runServe,
+	"pursuit":  runPursuit,
+	"replay":   runReplay,
+	"generate": runGenerate,
+}
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			cmd(os.Args[2:])
+			return
+		}
+	}
 
 	var configPath string
 	var eventPath string
+	var format string
+	var splitsPath string
+	var logPath string
+	var startListPath string
+	var groupBy string
+	var teams string
+	var bestN int
+	var groupsPath string
+	var splitStandingsPath string
+	var rangeStandingsPath string
+	var validation string
+	var follow bool
 
-	flag.StringVar(&configPath, "config", defaultConfigPath, "path to config file")
+	flag.StringVar(&configPath, "config", defaultConfigPath, "path to config file (.json, .yaml, .yml or .toml)")
 	flag.StringVar(&eventPath, "events", defaultEventPath, "path to events file")
+	flag.StringVar(&format, "format", string(reporting.FormatText), "final report format: text, json or csv, with json and csv the output log is written to standard error")
+	flag.StringVar(&splitsPath, "splits", "", "path to write per-lap splits in CSV format")
+	flag.StringVar(&splitStandingsPath, "split-standings", "", "path to write standings at every firing stage")
+	flag.StringVar(&rangeStandingsPath, "range-standings", "", "path to write the fastest on range leaderboard and range analytics")
+	flag.StringVar(&startListPath, "start-list", "", "path to the start list (.json or .csv), events for other competitors are rejected")
+	flag.StringVar(&groupBy, "group-by", "", "comma separated athlete fields to rank groups separately: category, gender, nation or club")
+	flag.StringVar(&teams, "teams", "", "athlete field to aggregate teams by: nation or club")
+	flag.IntVar(&bestN, "best", 3, "number of best times summed for a team, 0 to sum all finishers")
+	flag.StringVar(&groupsPath, "groups", "", "path to write the group and team standings to, standard output by default, required with json and csv formats")
+	flag.StringVar(&logPath, "log", "", "path to also write the output log to")
+	flag.StringVar(&validation, "validation", "strict", "event validation mode: strict or lenient")
+	flag.BoolVar(&follow, "follow", false, "wait for new events at the end of the events file and print intermediate standings in the text format, the race-closed event \"[time] 12\" ends the race")
 	flag.Parse()
 
+	reportFormat, err := reporting.ParseFormat(format)
+	if err != nil {
+		log.Fatalf("invalid flag -format: %v", err)
+	}
+
+	validationMode, err := task.ParseValidationMode(validation)
+	if err != nil {
+		log.Fatalf("invalid flag -validation: %v", err)
+	}
+
+	var groupFields []reporting.Field
+	if groupBy != "" {
+		groupFields, err = reporting.ParseFields(groupBy)
+		if err != nil {
+			log.Fatalf("invalid flag -group-by: %v", err)
+		}
+	}
+	var teamField reporting.Field
+	if teams != "" {
+		teamField, err = reporting.ParseField(teams)
+		if err != nil {
+			log.Fatalf("invalid flag -teams: %v", err)
+		}
+		if teamField != reporting.FieldNation && teamField != reporting.FieldClub {
+			log.Fatalf("invalid flag -teams: teams are aggregated by nation or club, not %s", teamField)
+		}
+	}
+	// Group standings are text only, they would break a machine-readable report on standard output
+	if (len(groupFields) > 0 || teamField != "") && reportFormat != reporting.FormatText && groupsPath == "" {
+		log.Fatalf("flag -groups is required with -format %s and -group-by or -teams", reportFormat)
+	}
+
 	f, err := os.Open(eventPath)
 	if err != nil {
 		log.Fatalf("failed to open file: %v", err)
 	}
 	defer f.Close()
-	sc := scannerEvent.NewScanner(f)
+
+	var sc task.ScannerEvent = scannerEvent.NewScanner(f)
+	if follow {
+		// The final report is printed when the race is closed, or on interrupt
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		sc = scannerEvent.NewFollowScanner(ctx, f, followPollInterval)
+	}
 
 	cfg := config.MustLoadConfig(configPath)
 
-	task := task.NewTask(cfg, sc)
+	var events sink.EventSink = logSink(reportFormat)
+	if logPath != "" {
+		logFile, err := sink.NewFileEventSink(logPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer logFile.Close()
+		events = sink.MultiEventSink(events, logFile)
+	}
+
+	var reports sink.ReportSink = sink.NewReportWriter(os.Stdout, reportFormat, cfg)
+	if splitsPath != "" {
+		splitsFile, err := os.Create(splitsPath)
+		if err != nil {
+			log.Fatalf("failed to create splits file: %v", err)
+		}
+		defer splitsFile.Close()
+		reports = sink.MultiReportSink(sink.NewSplitsWriter(splitsFile), reports)
+	}
+	if splitStandingsPath != "" {
+		splitStandingsFile, err := os.Create(splitStandingsPath)
+		if err != nil {
+			log.Fatalf("failed to create split standings file: %v", err)
+		}
+		defer splitStandingsFile.Close()
+		reports = sink.MultiReportSink(sink.NewSplitStandingsWriter(splitStandingsFile), reports)
+	}
+	if rangeStandingsPath != "" {
+		rangeStandingsFile, err := os.Create(rangeStandingsPath)
+		if err != nil {
+			log.Fatalf("failed to create range standings file: %v", err)
+		}
+		defer rangeStandingsFile.Close()
+		reports = sink.MultiReportSink(sink.NewRangeStandingsWriter(rangeStandingsFile), reports)
+	}
+
+	if len(groupFields) > 0 || teamField != "" {
+		var groupsOut io.Writer = os.Stdout
+		if groupsPath != "" {
+			groupsFile, err := os.Create(groupsPath)
+			if err != nil {
+				log.Fatalf("failed to create groups file: %v", err)
+			}
+			defer groupsFile.Close()
+			groupsOut = groupsFile
+		}
+		reports = sink.MultiReportSink(reports, sink.NewGroupsWriter(groupsOut, groupFields, teamField, bestN))
+	}
+
+	opts := []task.Option{
+		task.WithRaceOptions(raceOptions(startListPath)...),
+		task.WithEventSink(events),
+		task.WithReportSink(reports),
+		task.WithValidationMode(validationMode),
+		task.WithLiveStandings(follow),
+	}
+
+	task := task.NewTask(cfg, sc, opts...)
 	err = task.Execute()
 	if err != nil {
 		log.Fatalf("failed to run task: %v", err)
 	}
 
 }
+
+// logSink returns the sink of the output log for the report format.
+// Only the text report shares the standard output with the output log,
+// JSON and CSV reports are written alone so that the output can be parsed.
+func logSink(format reporting.Format) sink.EventSink {
+	if format == reporting.FormatText {
+		return sink.Stdout()
+	}
+	return sink.NewEventWriter(os.Stderr)
+}
+
+// raceOptions returns the options of the race for the start list path, which may be empty.
+func raceOptions(startListPath string) []task.RaceOption {
+	if startListPath == "" {
+		return nil
+	}
+	reg, err := registry.Load(startListPath)
+	if err != nil {
+		log.Fatalf("failed to load start list: %v", err)
+	}
+	return []task.RaceOption{task.WithRegistry(reg)}
+}
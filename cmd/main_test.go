@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Valery223/biathlon-test/internal/pursuit"
+)
+
+// TestMain runs the command instead of the tests when the test binary is started by runCommand.
+func TestMain(m *testing.M) {
+	if os.Getenv("BIATHLON_RUN_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCommand runs the command with the arguments and returns its standard output.
+func runCommand(t *testing.T, args ...string) []byte {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "BIATHLON_RUN_MAIN=1")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Command %v failed: %v\n%s", args, err, stderr.String())
+	}
+	return stdout.Bytes()
+}
+
+// runFailingCommand runs the command with the arguments and returns its combined output and error.
+func runFailingCommand(t *testing.T, args ...string) ([]byte, error) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "BIATHLON_RUN_MAIN=1")
+	return cmd.CombinedOutput()
+}
+
+// TestMain_MachineReadableFormats checks that the JSON and CSV reports are the only output on stdout,
+// so they can be parsed and read back as the results of a previous race.
+func TestMain_MachineReadableFormats(t *testing.T) {
+	sample := []string{
+		"-config", filepath.Join("..", defaultConfigPath),
+		"-events", filepath.Join("..", defaultEventPath),
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		out := runCommand(t, append(sample, "-format", "json")...)
+		var reports []map[string]any
+		if err := json.Unmarshal(out, &reports); err != nil {
+			t.Fatalf("Stdout is not JSON: %v\n%s", err, out)
+		}
+		if len(reports) != 5 {
+			t.Errorf("Reports: got %d, want 5", len(reports))
+		}
+		loadResults(t, "results.json", out)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		out := runCommand(t, append(sample, "-format", "csv")...)
+		records, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+		if err != nil {
+			t.Fatalf("Stdout is not CSV: %v\n%s", err, out)
+		}
+		if len(records) != 6 {
+			t.Errorf("Records: got %d, want header and 5 rows", len(records))
+		}
+		loadResults(t, "results.csv", out)
+	})
+}
+
+// loadResults checks that the report can be used as the results of a previous race by the pursuit command.
+func loadResults(t *testing.T, name string, report []byte) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, report, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pursuit.LoadResults(path); err != nil {
+		t.Errorf("LoadResults failed: %v", err)
+	}
+}
+
+// TestMain_GroupsWithMachineReadableFormat checks that group standings are not mixed into a JSON report.
+func TestMain_GroupsWithMachineReadableFormat(t *testing.T) {
+	args := []string{
+		"-config", filepath.Join("..", defaultConfigPath),
+		"-events", filepath.Join("..", defaultEventPath),
+		"-start-list", filepath.Join("..", "internal", "task", "testdata", "start_list", "start_list.csv"),
+		"-format", "json",
+		"-group-by", "nation",
+	}
+
+	if out, err := runFailingCommand(t, args...); err == nil || !bytes.Contains(out, []byte("-groups is required")) {
+		t.Errorf("Command without -groups: got %v\n%s", err, out)
+	}
+
+	groupsPath := filepath.Join(t.TempDir(), "groups.txt")
+	out := runCommand(t, append(args, "-groups", groupsPath)...)
+	var reports []map[string]any
+	if err := json.Unmarshal(out, &reports); err != nil {
+		t.Fatalf("Stdout is not JSON: %v\n%s", err, out)
+	}
+	if groups, err := os.ReadFile(groupsPath); err != nil || len(groups) == 0 {
+		t.Errorf("Groups file: got %q, %v", groups, err)
+	}
+}
+
+func TestMain_TeamsField(t *testing.T) {
+	out, err := runFailingCommand(t, "-teams", "gender")
+	if err == nil || !bytes.Contains(out, []byte("teams are aggregated by nation or club")) {
+		t.Errorf("Command with -teams gender: got %v\n%s", err, out)
+	}
+}
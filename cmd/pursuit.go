@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/pursuit"
+	"github.com/Valery223/biathlon-test/internal/racetime"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+	"github.com/Valery223/biathlon-test/internal/sink"
+	"github.com/Valery223/biathlon-test/internal/task"
+)
+
+// runPursuit generates the handicapped start times of a pursuit from the results of a previous race.
+// The leader starts at the start time of the config, the others start later by their time behind.
+// Without -events the draw events are printed, issued at the start time of the leader.
+// With -events the pursuit race is run with the draw events inserted after the registrations.
+func runPursuit(args []string) {
+	fs := flag.NewFlagSet("pursuit", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config file of the pursuit (.json, .yaml, .yml or .toml)")
+	resultsPath := fs.String("results", "", "path to the results of the previous race (.json report or .csv results)")
+	maxBehind := fs.String("cap", "", "maximum time behind the leader, e.g. 00:03:00 (optional)")
+	eventPath := fs.String("events", "", "path to the events file of the pursuit race (optional)")
+	drawPath := fs.String("draw", "", "path to write the draw events to (optional)")
+	format := fs.String("format", string(reporting.FormatText), "final report format: text, json or csv, with json and csv the output log is written to standard error")
+	startListPath := fs.String("start-list", "", "path to the start list (.json or .csv), events for other competitors are rejected")
+	validation := fs.String("validation", "strict", "event validation mode: strict or lenient")
+	fs.Parse(args)
+
+	if *resultsPath == "" {
+		log.Fatal("flag -results is required")
+	}
+	var maxBehindDuration time.Duration
+	if *maxBehind != "" {
+		var err error
+		maxBehindDuration, err = racetime.ParseDuration(*maxBehind)
+		if err != nil || maxBehindDuration <= 0 {
+			log.Fatalf("invalid flag -cap %q", *maxBehind)
+		}
+	}
+	reportFormat, err := reporting.ParseFormat(*format)
+	if err != nil {
+		log.Fatalf("invalid flag -format: %v", err)
+	}
+	validationMode, err := task.ParseValidationMode(*validation)
+	if err != nil {
+		log.Fatalf("invalid flag -validation: %v", err)
+	}
+
+	cfg := config.MustLoadConfig(*configPath)
+
+	results, err := pursuit.LoadResults(*resultsPath)
+	if err != nil {
+		log.Fatalf("failed to load results: %v", err)
+	}
+	starts := pursuit.Draw(results, cfg.StartTime, maxBehindDuration)
+
+	if *drawPath != "" {
+		f, err := os.Create(*drawPath)
+		if err != nil {
+			log.Fatalf("failed to create draw file: %v", err)
+		}
+		defer f.Close()
+		if err := writeDraw(f, starts, cfg.StartTime); err != nil {
+			log.Fatalf("failed to write draw: %v", err)
+		}
+	}
+
+	if *eventPath == "" {
+		if *drawPath == "" {
+			if err := writeDraw(os.Stdout, starts, cfg.StartTime); err != nil {
+				log.Fatalf("failed to write draw: %v", err)
+			}
+		}
+		return
+	}
+
+	f, err := os.Open(*eventPath)
+	if err != nil {
+		log.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	sc := pursuit.NewDrawScanner(scannerEvent.NewScanner(f), starts)
+	t := task.NewTask(cfg, sc,
+		task.WithEventSink(logSink(reportFormat)),
+		task.WithReportSink(sink.NewReportWriter(os.Stdout, reportFormat, cfg)),
+		task.WithValidationMode(validationMode),
+		task.WithRaceOptions(raceOptions(*startListPath)...))
+	if err := t.Execute(); err != nil {
+		log.Fatalf("failed to run task: %v", err)
+	}
+}
+
+// writeDraw writes the draw events issued at the given time in the events file format.
+func writeDraw(w io.Writer, starts []pursuit.Start, at time.Time) error {
+	for _, s := range starts {
+		if _, err := fmt.Fprintln(w, scannerEvent.FormatLine(s.DrawEvent(at))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
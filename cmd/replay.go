@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+	"github.com/Valery223/biathlon-test/internal/server"
+	"github.com/Valery223/biathlon-test/internal/sink"
+	"github.com/Valery223/biathlon-test/internal/task"
+)
+
+// runReplay replays a recorded events file at the pace the events happened.
+// The output log is written as events become due, so sinks and the optional HTTP API
+// see the same stream as during the race.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config file (.json, .yaml, .yml or .toml)")
+	eventPath := fs.String("events", defaultEventPath, "path to events file")
+	speed := fs.String("speed", "1x", "replay speed: 1x, 10x, any multiplier or max")
+	addr := fs.String("addr", "", "address to serve the HTTP API on during the replay (optional)")
+	format := fs.String("format", string(reporting.FormatText), "report format: text, json or csv, intermediate standings are printed in the text format only, with json and csv the output log is written to standard error")
+	startListPath := fs.String("start-list", "", "path to the start list (.json or .csv), events for other competitors are rejected")
+	validation := fs.String("validation", "strict", "event validation mode: strict or lenient")
+	fs.Parse(args)
+
+	speedMultiplier, err := scannerEvent.ParseSpeed(*speed)
+	if err != nil {
+		log.Fatalf("invalid flag -speed: %v", err)
+	}
+	reportFormat, err := reporting.ParseFormat(*format)
+	if err != nil {
+		log.Fatalf("invalid flag -format: %v", err)
+	}
+	validationMode, err := task.ParseValidationMode(*validation)
+	if err != nil {
+		log.Fatalf("invalid flag -validation: %v", err)
+	}
+
+	cfg := config.MustLoadConfig(*configPath)
+
+	f, err := os.Open(*eventPath)
+	if err != nil {
+		log.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	// An interrupt stops the replay and prints the report of the events replayed so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sc := scannerEvent.NewPacedScanner(ctx, scannerEvent.NewScanner(f), speedMultiplier)
+	t := task.NewTask(cfg, sc,
+		task.WithEventSink(logSink(reportFormat)),
+		task.WithReportSink(sink.NewReportWriter(os.Stdout, reportFormat, cfg)),
+		task.WithValidationMode(validationMode),
+		task.WithLiveStandings(true),
+		task.WithRaceOptions(raceOptions(*startListPath)...))
+
+	if *addr != "" {
+		srv := &http.Server{Addr: *addr, Handler: server.New(t.Race())}
+		go func() {
+			log.Printf("Listening on %s", *addr)
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("failed to serve: %v", err)
+			}
+		}()
+		defer srv.Close()
+	}
+
+	if err := t.Execute(); err != nil {
+		log.Fatalf("failed to run task: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+	"github.com/Valery223/biathlon-test/internal/server"
+	"github.com/Valery223/biathlon-test/internal/task"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// runServe starts the HTTP API serving the live state of the race.
+// Events are pushed with POST /events, and optionally read from a followed events file.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	configPath := fs.String("config", defaultConfigPath, "path to config file (.json, .yaml, .yml or .toml)")
+	eventPath := fs.String("events", "", "path to an events file to follow (optional)")
+	startListPath := fs.String("start-list", "", "path to the start list (.json or .csv), events for other competitors are rejected")
+	validation := fs.String("validation", "strict", "event validation mode for the events file: strict or lenient")
+	fs.Parse(args)
+
+	validationMode, err := task.ParseValidationMode(*validation)
+	if err != nil {
+		log.Fatalf("invalid flag -validation: %v", err)
+	}
+
+	cfg := config.MustLoadConfig(*configPath)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	raceOpts := raceOptions(*startListPath)
+	race := task.NewRace(cfg, raceOpts...)
+	taskDone := make(chan struct{})
+	if *eventPath != "" {
+		f, err := os.Open(*eventPath)
+		if err != nil {
+			log.Fatalf("failed to open file: %v", err)
+		}
+		defer f.Close()
+
+		t := task.NewTask(cfg, scannerEvent.NewFollowScanner(ctx, f, followPollInterval),
+			task.WithValidationMode(validationMode),
+			task.WithRaceOptions(raceOpts...))
+		race = t.Race()
+		go func() {
+			defer close(taskDone)
+			if err := t.Execute(); err != nil {
+				log.Printf("failed to run task: %v", err)
+			}
+		}()
+	} else {
+		close(taskDone)
+	}
+
+	srv := &http.Server{
+		Addr:    *addr,
+		Handler: server.New(race),
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed to shut down server: %v", err)
+		}
+	}()
+
+	log.Printf("Listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("failed to serve: %v", err)
+	}
+
+	// Wait for the final report of the followed events file
+	<-taskDone
+}
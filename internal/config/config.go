@@ -2,11 +2,31 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"time"
+
+	"github.com/Valery223/biathlon-test/internal/racetime"
+)
+
+// RaceFormat is the format of a race, it defines how results are scored.
+type RaceFormat string
+
+// Supported race formats.
+const (
+	// RaceSprint adds a penalty loop for every miss. It is the default format.
+	RaceSprint RaceFormat = "sprint"
+	// RaceIndividual adds a fixed time for every miss instead of penalty loops.
+	RaceIndividual RaceFormat = "individual"
+	// RaceMassStart starts all competitors together at the start time, there is no draw.
+	RaceMassStart RaceFormat = "massStart"
 )
 
+// DefaultPenaltySeconds is the time added for a miss in the individual format when not configured.
+const DefaultPenaltySeconds = 60
+
 type Config struct {
 	Laps          int           `json:"laps"`
 	LapLength     int           `json:"lapLen"`
@@ -14,50 +34,256 @@ type Config struct {
 	FiringLines   int           `json:"firingLines"`
 	StartTime     time.Time     `json:"start"`
 	StartDelta    time.Duration `json:"startDelta"`
+	// PenaltyLoopTime is the expected time of a single penalty loop.
+	// It is used to estimate the number of skied loops when events do not report it, 0 disables the estimation.
+	PenaltyLoopTime time.Duration `json:"penaltyLoopTime"`
+	// SkippedLoopPenalty is the time added to the total time for every skipped penalty loop, 0 disables it.
+	SkippedLoopPenalty time.Duration `json:"skippedLoopPenalty"`
+	// Format is the race format, RaceSprint if empty.
+	Format RaceFormat `json:"format"`
+	// PenaltySeconds is the time added for every miss in the individual format.
+	PenaltySeconds int `json:"penaltySeconds"`
+}
+
+// FieldError describes a problem with a single configuration field.
+type FieldError struct {
+	Field   string // JSON name of the field
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func fieldErrorf(field, format string, args ...any) *FieldError {
+	return &FieldError{Field: field, Message: fmt.Sprintf(format, args...)}
 }
 
+// MustLoadConfig loads the configuration from the given path.
+// It terminates the process if the configuration cannot be loaded or is invalid.
 func MustLoadConfig(configPath string) *Config {
+	cfg, err := Load(configPath)
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	return cfg
+}
+
+// Load reads, parses and validates the configuration from the given path.
+// The file format is chosen by the file extension, see DecoderFor.
+// All problems found in the configuration are reported in a single error.
+func Load(configPath string) (*Config, error) {
+	decode, err := DecoderFor(configPath)
+	if err != nil {
+		return nil, err
+	}
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		log.Fatalf("failed to read config file: %v", err)
+		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Временная структура для парсинга строковых дат
-	type tempConfig struct {
-		Laps          int    `json:"laps"`
-		LapLength     int    `json:"lapLen"`
-		PenaltyLength int    `json:"penaltyLen"`
-		FiringLines   int    `json:"firingLines"`
-		StartTime     string `json:"start"`
-		StartDelta    string `json:"startDelta"`
+	cfg, err := Parse(data, decode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
 	}
+	return cfg, nil
+}
 
-	var tmp tempConfig
-	if err := json.Unmarshal(data, &tmp); err != nil {
-		log.Fatalf("failed to parse config: %v", err)
+// Parse decodes the configuration data with the given decoder and validates it.
+// All problems found in the configuration are reported in a single error.
+func Parse(data []byte, decode DecodeFunc) (*Config, error) {
+	var raw rawConfig
+	if err := decode(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	startTime, err := time.Parse("15:04:05.000", tmp.StartTime)
-	if err != nil {
-		log.Fatalf("failed to parse start time: %v", err)
+	cfg, err := raw.toConfig()
+	errs := unwrapJoined(err)
+	failed := make(map[string]bool) // Fields which could not be parsed
+	for _, e := range errs {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			failed[fe.Field] = true
+		}
 	}
 
-	t, err := time.Parse("15:04:05", tmp.StartDelta)
-	if err != nil {
-		log.Fatalf("failed to parse start delta time: %v", err)
+	for _, e := range unwrapJoined(cfg.Validate()) {
+		var fe *FieldError
+		if errors.As(e, &fe) && failed[fe.Field] {
+			// Already reported as a parse error
+			continue
+		}
+		errs = append(errs, e)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+
+	return cfg, nil
+}
+
+// rawConfig is the file representation of Config with times kept as strings.
+type rawConfig struct {
+	Laps          int    `json:"laps" yaml:"laps" toml:"laps"`
+	LapLength     int    `json:"lapLen" yaml:"lapLen" toml:"lapLen"`
+	PenaltyLength int    `json:"penaltyLen" yaml:"penaltyLen" toml:"penaltyLen"`
+	FiringLines   int    `json:"firingLines" yaml:"firingLines" toml:"firingLines"`
+	StartTime     string `json:"start" yaml:"start" toml:"start"`
+	StartDelta    string `json:"startDelta" yaml:"startDelta" toml:"startDelta"`
+
+	PenaltyLoopTime    string `json:"penaltyLoopTime,omitempty" yaml:"penaltyLoopTime" toml:"penaltyLoopTime"`
+	SkippedLoopPenalty string `json:"skippedLoopPenalty,omitempty" yaml:"skippedLoopPenalty" toml:"skippedLoopPenalty"`
+
+	Format         RaceFormat `json:"format,omitempty" yaml:"format" toml:"format"`
+	PenaltySeconds int        `json:"penaltySeconds,omitempty" yaml:"penaltySeconds" toml:"penaltySeconds"`
+}
+
+// toConfig converts the raw configuration into Config.
+// "start" and "startDelta" accept "HH:MM:SS", "HH:MM:SS.sss" or a Go duration string.
+// A missing format defaults to RaceSprint, a missing penaltySeconds of the individual format
+// defaults to DefaultPenaltySeconds. Other missing values are left zero and reported by Validate.
+// Unparsable values are reported as a joined *FieldError, the remaining fields are still set.
+func (raw rawConfig) toConfig() (*Config, error) {
+	c := &Config{
+		Laps:           raw.Laps,
+		LapLength:      raw.LapLength,
+		PenaltyLength:  raw.PenaltyLength,
+		FiringLines:    raw.FiringLines,
+		Format:         raw.Format,
+		PenaltySeconds: raw.PenaltySeconds,
+	}
+	if c.Format == "" {
+		c.Format = RaceSprint
+	}
+	if c.Format == RaceIndividual && c.PenaltySeconds == 0 {
+		c.PenaltySeconds = DefaultPenaltySeconds
 	}
-	startDelta := time.Duration(t.Hour())*time.Hour +
-		time.Duration(t.Minute())*time.Minute +
-		time.Duration(t.Second())*time.Second +
-		time.Duration(t.Nanosecond())
 
-	return &Config{
-		Laps:          tmp.Laps,
-		LapLength:     tmp.LapLength,
-		PenaltyLength: tmp.PenaltyLength,
-		FiringLines:   tmp.FiringLines,
-		StartTime:     startTime,
-		StartDelta:    startDelta,
+	var errs []error
+	if raw.StartTime != "" {
+		startTime, err := racetime.ParseTimeOfDay(raw.StartTime)
+		if err != nil {
+			errs = append(errs, fieldErrorf("start", "%v", err))
+		}
+		c.StartTime = startTime
 	}
+	if raw.StartDelta != "" {
+		startDelta, err := racetime.ParseDuration(raw.StartDelta)
+		if err != nil {
+			errs = append(errs, fieldErrorf("startDelta", "%v", err))
+		}
+		c.StartDelta = startDelta
+	}
+	if raw.PenaltyLoopTime != "" {
+		penaltyLoopTime, err := racetime.ParseDuration(raw.PenaltyLoopTime)
+		if err != nil {
+			errs = append(errs, fieldErrorf("penaltyLoopTime", "%v", err))
+		}
+		c.PenaltyLoopTime = penaltyLoopTime
+	}
+	if raw.SkippedLoopPenalty != "" {
+		skippedLoopPenalty, err := racetime.ParseDuration(raw.SkippedLoopPenalty)
+		if err != nil {
+			errs = append(errs, fieldErrorf("skippedLoopPenalty", "%v", err))
+		}
+		c.SkippedLoopPenalty = skippedLoopPenalty
+	}
+
+	return c, errors.Join(errs...)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Time formats are the same as in configuration files, values are not validated.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var raw rawConfig
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	cfg, err := raw.toConfig()
+	*c = *cfg
+	return err
+}
+
+// MarshalJSON implements json.Marshaler.
+// Times are written in the "HH:MM:SS" format, with milliseconds only when they are not zero.
+func (c Config) MarshalJSON() ([]byte, error) {
+	raw := rawConfig{
+		Laps:           c.Laps,
+		LapLength:      c.LapLength,
+		PenaltyLength:  c.PenaltyLength,
+		FiringLines:    c.FiringLines,
+		StartTime:      racetime.FormatTimeOfDay(c.StartTime),
+		StartDelta:     racetime.FormatDuration(c.StartDelta),
+		PenaltySeconds: c.PenaltySeconds,
+	}
+	if c.Format != RaceSprint {
+		raw.Format = c.Format
+	}
+	if c.PenaltyLoopTime != 0 {
+		raw.PenaltyLoopTime = racetime.FormatDuration(c.PenaltyLoopTime)
+	}
+	if c.SkippedLoopPenalty != 0 {
+		raw.SkippedLoopPenalty = racetime.FormatDuration(c.SkippedLoopPenalty)
+	}
+	return json.Marshal(raw)
+}
+
+// unwrapJoined returns the errors joined by errors.Join, or err itself if it is not joined.
+func unwrapJoined(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// Validate checks the configuration values.
+// It returns an error joining a *FieldError for every invalid field, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Laps <= 0 {
+		errs = append(errs, fieldErrorf("laps", "must be positive, got %d", c.Laps))
+	}
+	if c.LapLength <= 0 {
+		errs = append(errs, fieldErrorf("lapLen", "must be positive, got %d", c.LapLength))
+	}
+	// Penalty loops are not skied in the individual format
+	if c.Format != RaceIndividual && c.PenaltyLength <= 0 {
+		errs = append(errs, fieldErrorf("penaltyLen", "must be positive, got %d", c.PenaltyLength))
+	}
+	if c.FiringLines < 0 {
+		errs = append(errs, fieldErrorf("firingLines", "must not be negative, got %d", c.FiringLines))
+	}
+	if c.StartTime.IsZero() {
+		errs = append(errs, fieldErrorf("start", "is required"))
+	}
+	switch {
+	case c.Format == RaceMassStart:
+		// All competitors share the start time, there is no start interval
+	case c.StartDelta == 0:
+		errs = append(errs, fieldErrorf("startDelta", "is required"))
+	case c.StartDelta < 0:
+		errs = append(errs, fieldErrorf("startDelta", "must be positive, got %s", c.StartDelta))
+	}
+	switch c.Format {
+	case "", RaceSprint, RaceIndividual, RaceMassStart:
+	default:
+		errs = append(errs, fieldErrorf("format", "unknown race format %q", c.Format))
+	}
+	if c.PenaltySeconds < 0 {
+		errs = append(errs, fieldErrorf("penaltySeconds", "must not be negative, got %d", c.PenaltySeconds))
+	}
+	if c.PenaltyLoopTime < 0 {
+		errs = append(errs, fieldErrorf("penaltyLoopTime", "must not be negative, got %s", c.PenaltyLoopTime))
+	}
+	if c.SkippedLoopPenalty < 0 {
+		errs = append(errs, fieldErrorf("skippedLoopPenalty", "must not be negative, got %s", c.SkippedLoopPenalty))
+	}
+
+	return errors.Join(errs...)
 }
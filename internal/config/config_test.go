@@ -0,0 +1,166 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	return writeConfigFile(t, "config.json", content)
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		path := writeConfig(t, `{"laps": 2, "lapLen": 3500, "penaltyLen": 150, "firingLines": 2, "start": "10:00:00.000", "startDelta": "00:01:30"}`)
+
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Laps != 2 || cfg.LapLength != 3500 || cfg.PenaltyLength != 150 || cfg.FiringLines != 2 {
+			t.Errorf("Load: unexpected config %+v", cfg)
+		}
+		if got := cfg.StartTime.Format("15:04:05.000"); got != "10:00:00.000" {
+			t.Errorf("StartTime: got %s, want 10:00:00.000", got)
+		}
+		if cfg.StartDelta != 90*time.Second {
+			t.Errorf("StartDelta: got %v, want %v", cfg.StartDelta, 90*time.Second)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := writeConfig(t, `{"laps": 0, "lapLen": -1, "penaltyLen": 150, "firingLines": 2, "start": "ten o'clock"}`)
+
+		_, err := Load(path)
+		if err == nil {
+			t.Fatal("Load: expected error, got nil")
+		}
+
+		var fe *FieldError
+		if !errors.As(err, &fe) {
+			t.Errorf("Load: error %v does not contain a FieldError", err)
+		}
+		for _, field := range []string{"laps:", "lapLen:", "start: invalid time", "startDelta: is required"} {
+			if !strings.Contains(err.Error(), field) {
+				t.Errorf("Load: error %q does not mention %q", err, field)
+			}
+		}
+		if strings.Contains(err.Error(), "start: is required") {
+			t.Errorf("Load: error %q reports unparsable start twice", err)
+		}
+	})
+
+	t.Run("race format", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, `{"laps": 4, "lapLen": 5000, "firingLines": 1, "start": "10:00:00", "startDelta": "30s", "format": "individual"}`))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		// Penalty loops are not required in the individual format, the miss penalty defaults to a minute
+		if cfg.Format != RaceIndividual || cfg.PenaltySeconds != DefaultPenaltySeconds {
+			t.Errorf("Load: unexpected format %q and penalty %d", cfg.Format, cfg.PenaltySeconds)
+		}
+
+		cfg, err = Load(writeConfig(t, `{"laps": 2, "lapLen": 3500, "penaltyLen": 150, "start": "10:00:00", "startDelta": "30s"}`))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Format != RaceSprint || cfg.PenaltySeconds != 0 {
+			t.Errorf("Load: unexpected format %q and penalty %d", cfg.Format, cfg.PenaltySeconds)
+		}
+
+		// There is no start interval in the mass start
+		cfg, err = Load(writeConfig(t, `{"laps": 2, "lapLen": 3500, "penaltyLen": 150, "start": "10:00:00", "format": "massStart"}`))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Format != RaceMassStart {
+			t.Errorf("Load: unexpected format %q", cfg.Format)
+		}
+
+		_, err = Load(writeConfig(t, `{"laps": 2, "lapLen": 3500, "start": "10:00:00", "startDelta": "30s", "format": "relay"}`))
+		for _, field := range []string{"format: unknown race format", "penaltyLen:"} {
+			if err == nil || !strings.Contains(err.Error(), field) {
+				t.Errorf("Load: error %v does not mention %q", err, field)
+			}
+		}
+	})
+}
+
+func TestConfig_JSON(t *testing.T) {
+	path := writeConfig(t, `{"laps": 2, "lapLen": 3651, "penaltyLen": 50, "firingLines": 1, "start": "09:30:00", "startDelta": "30.5s"}`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.StartDelta != 30*time.Second+500*time.Millisecond {
+		t.Errorf("StartDelta: got %v, want 30.5s", cfg.StartDelta)
+	}
+
+	got, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	want := `{"laps":2,"lapLen":3651,"penaltyLen":50,"firingLines":1,"start":"09:30:00","startDelta":"00:00:30.500"}`
+	if string(got) != want {
+		t.Errorf("json.Marshal:\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestLoad_Formats(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "config.yaml",
+			content: "laps: 2\nlapLen: 3651\npenaltyLen: 50\nfiringLines: 1\nstart: \"09:30:00\"\nstartDelta: 30s\n",
+		},
+		{
+			name:    "config.yml",
+			content: "laps: 2\nlapLen: 3651\npenaltyLen: 50\nfiringLines: 1\nstart: 09:30:00.000\nstartDelta: 00:00:30\n",
+		},
+		{
+			name:    "config.toml",
+			content: "laps = 2\nlapLen = 3651\npenaltyLen = 50\nfiringLines = 1\nstart = \"09:30:00\"\nstartDelta = \"00:00:30\"\n",
+		},
+	}
+
+	want, _ := time.Parse("15:04:05.000", "09:30:00.000")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := Load(writeConfigFile(t, tc.name, tc.content))
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if cfg.Laps != 2 || cfg.LapLength != 3651 || cfg.PenaltyLength != 50 || cfg.FiringLines != 1 {
+				t.Errorf("Load: unexpected config %+v", cfg)
+			}
+			if !cfg.StartTime.Equal(want) {
+				t.Errorf("StartTime: got %v, want %v", cfg.StartTime, want)
+			}
+			if cfg.StartDelta != 30*time.Second {
+				t.Errorf("StartDelta: got %v, want 30s", cfg.StartDelta)
+			}
+		})
+	}
+
+	if _, err := Load(writeConfigFile(t, "config.ini", "laps=2")); err == nil {
+		t.Errorf("Load: expected error for unsupported extension")
+	}
+}
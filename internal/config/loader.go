@@ -0,0 +1,33 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeFunc decodes configuration data into v.
+type DecodeFunc func(data []byte, v any) error
+
+// decoders maps supported file extensions to their decoders.
+var decoders = map[string]DecodeFunc{
+	".json": json.Unmarshal,
+	".yaml": yaml.Unmarshal,
+	".yml":  yaml.Unmarshal,
+	".toml": toml.Unmarshal,
+}
+
+// DecoderFor returns the decoder for the configuration file
+// chosen by its extension: .json, .yaml, .yml or .toml.
+func DecoderFor(configPath string) (DecodeFunc, error) {
+	ext := strings.ToLower(filepath.Ext(configPath))
+	decode, ok := decoders[ext]
+	if !ok {
+		return nil, fmt.Errorf("unsupported config file extension %q", ext)
+	}
+	return decode, nil
+}
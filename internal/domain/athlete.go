@@ -0,0 +1,40 @@
+package domain
+
+import "fmt"
+
+// Athlete holds the start list data of a competitor.
+type Athlete struct {
+	Bib      int
+	Name     string
+	Nation   string
+	Club     string
+	Gender   string
+	Category string
+}
+
+// IsZero reports whether the athlete data is missing.
+func (a Athlete) IsZero() bool {
+	return a == Athlete{}
+}
+
+// String returns the bib, the name and the nation of the athlete as shown in the reports: "#7 Anna Berg (SWE)".
+// Missing parts are omitted.
+func (a Athlete) String() string {
+	var result string
+	if a.Bib > 0 {
+		result = fmt.Sprintf("#%d", a.Bib)
+	}
+	if a.Name != "" {
+		if result != "" {
+			result += " "
+		}
+		result += a.Name
+	}
+	if a.Nation != "" {
+		if result != "" {
+			result += " "
+		}
+		result += "(" + a.Nation + ")"
+	}
+	return result
+}
@@ -1,19 +1,25 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Competitor represents a participant in the race.
 // It holds their personal details, race status, and performance data.
 type Competitor struct {
 	ID             int
+	Athlete        Athlete // Start list data, zero if the race is run without a start list
 	Status         Status
 	ScheduledStart time.Time
 	ActualStart    time.Time
 	Laps           []Lap
 	PenaltyLaps    []PenaltyLap
+	FiringStages   []FiringStage
 	Shots          int
 	FiringCount    int // Number of times the competitor was on the firing line
 	CurrentLap     int
+	Disqualified   bool // Set when the competitor did not start during the start interval
 }
 
 // Status represents the current state of a competitor in the race.
@@ -23,14 +29,88 @@ const (
 	StatusFinished Status = iota
 	StatusNotStarted
 	StatusNotFinished
+	StatusRunning // The competitor has started and is still on the course
 )
 
+// String returns the name of the status as used in the final report.
+func (s Status) String() string {
+	switch s {
+	case StatusFinished:
+		return "Finished"
+	case StatusNotStarted:
+		return "NotStarted"
+	case StatusNotFinished:
+		return "NotFinished"
+	case StatusRunning:
+		return "Running"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
 func NewCompetitor(id int) *Competitor {
 	return &Competitor{
-		ID:          id,
-		Status:      StatusNotStarted,
-		Laps:        make([]Lap, 0),
-		PenaltyLaps: make([]PenaltyLap, 0),
-		Shots:       0,
+		ID:           id,
+		Status:       StatusNotStarted,
+		Laps:         make([]Lap, 0),
+		PenaltyLaps:  make([]PenaltyLap, 0),
+		FiringStages: make([]FiringStage, 0),
+		Shots:        0,
+	}
+}
+
+// ExpectedPenaltyLoops returns the number of penalty loops the competitor has to ski:
+// one loop for every target missed on the firing stages followed by a completed lap.
+// The penalty area lies between the firing range and the end of the lap,
+// so the loops of a stage are not due before the competitor has ended the lap.
+func (c *Competitor) ExpectedPenaltyLoops() int {
+	passed := c.lastLapEnd()
+	loops := 0
+	for _, stage := range c.FiringStages {
+		if !stage.Exit.IsZero() && stage.Exit.Before(passed) {
+			loops += stage.Misses()
+		}
+	}
+	return loops
+}
+
+// SkiedPenaltyLoops returns the number of penalty loops the competitor has skied
+// before the end of the last completed lap, see ExpectedPenaltyLoops.
+// The second result is false if the number of loops of some of these visits of the penalty area is unknown.
+func (c *Competitor) SkiedPenaltyLoops() (int, bool) {
+	passed := c.lastLapEnd()
+	loops := 0
+	for _, lap := range c.PenaltyLaps {
+		if !lap.Start.Before(passed) {
+			continue
+		}
+		if lap.Loops == 0 {
+			return 0, false
+		}
+		loops += lap.Loops
+	}
+	return loops, true
+}
+
+// lastLapEnd returns the end of the last completed lap, zero if the competitor has not completed a lap.
+func (c *Competitor) lastLapEnd() time.Time {
+	for i := len(c.Laps) - 1; i >= 0; i-- {
+		if !c.Laps[i].End.IsZero() {
+			return c.Laps[i].End
+		}
+	}
+	return time.Time{}
+}
+
+// CurrentFiringStage returns the firing stage the competitor is currently on,
+// or nil if the competitor is not on a firing range.
+func (c *Competitor) CurrentFiringStage() *FiringStage {
+	if len(c.FiringStages) == 0 {
+		return nil
+	}
+	stage := &c.FiringStages[len(c.FiringStages)-1]
+	if !stage.Exit.IsZero() {
+		return nil
 	}
+	return stage
 }
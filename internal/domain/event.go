@@ -21,7 +21,9 @@ const (
 	EventCompetitorLeftPenalty     EventID = 9
 	EventCompetitorEndedMainLap    EventID = 10
 	EventCompetitorCanNotContinue  EventID = 11
+	EventRaceClosed                EventID = 12 // Sentinel event, no more events will follow
 	EventCompetitorDisqualified    EventID = 32
+	EventCompetitorFinished        EventID = 33
 )
 
 // ScannerEvent is an interface for components that can provide race events.
@@ -71,8 +73,15 @@ func (e *Event) Format() string {
 		return fmt.Sprintf("[%s] The competitor(%d) ended the main lap", timestamp, e.CompetitorID)
 	case EventCompetitorCanNotContinue:
 		return fmt.Sprintf("[%s] The competitor(%d) can`t continue: %s", timestamp, e.CompetitorID, e.Comments)
+	case EventRaceClosed:
+		return fmt.Sprintf("[%s] The race is closed", timestamp)
 	case EventCompetitorDisqualified:
 		return fmt.Sprintf("[%s] The competitor(%d) is disqualified", timestamp, e.CompetitorID)
+	case EventCompetitorFinished:
+		if e.Comments == "" {
+			return fmt.Sprintf("[%s] The competitor(%d) has finished", timestamp, e.CompetitorID)
+		}
+		return fmt.Sprintf("[%s] The competitor(%d) has finished, last lap %s", timestamp, e.CompetitorID, e.Comments)
 	default:
 		return fmt.Sprintf("[%s] Unknown event(%d) for competitor(%d)", timestamp, e.ID, e.CompetitorID)
 	}
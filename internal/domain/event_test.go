@@ -30,6 +30,16 @@ func TestEvent_Format(t *testing.T) {
 			event: Event{Time: fixedTime, ID: EventTargetHit, CompetitorID: 3, Comments: "Target5"},
 			want:  fmt.Sprintf("[%s] The target(Target5) has been hit by competitor(3)", formattedTime),
 		},
+		{
+			name:  "CompetitorFinished",
+			event: Event{Time: fixedTime, ID: EventCompetitorFinished, CompetitorID: 5, Comments: "00:12:38.610"},
+			want:  fmt.Sprintf("[%s] The competitor(5) has finished, last lap 00:12:38.610", formattedTime),
+		},
+		{
+			name:  "RaceClosed",
+			event: Event{Time: fixedTime, ID: EventRaceClosed},
+			want:  fmt.Sprintf("[%s] The race is closed", formattedTime),
+		},
 		{
 			name:  "UnknownEvent",
 			event: Event{Time: fixedTime, ID: EventID(99), CompetitorID: 4},
@@ -7,12 +7,14 @@ type Lap struct {
 	Start     time.Time
 	End       time.Time
 	TargetHit int
-	// FiringStart time.Time // Not used
-	// FiringEnd   time.Time // Not used
+	// Times on the firing ranges are recorded in Competitor.FiringStages
 }
 
-// PenaltyLap represents a single penalty lap.
+// PenaltyLap represents a single visit of the penalty area.
 type PenaltyLap struct {
 	Start time.Time
 	End   time.Time
+	// Loops is the number of penalty loops skied during the visit, 0 if unknown.
+	// It is set from the extraParams of event 9 or estimated from the duration of the visit.
+	Loops int
 }
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TargetsPerStage defines the number of targets on a firing line.
+const TargetsPerStage = 5
+
+// FiringStage represents a single visit of a competitor to a firing range.
+type FiringStage struct {
+	Range int // Number of the firing range (extraParams of event 5)
+	Enter time.Time
+	Exit  time.Time
+	// Targets holds hit flags, Targets[i] is true if target i+1 has been hit.
+	Targets [TargetsPerStage]bool
+	// HitTimes holds the times of the hits in the order they happened.
+	HitTimes []time.Time
+}
+
+// Hit marks the target as hit at the given time.
+// It returns an error if the target number is out of range or the target has already been hit.
+func (s *FiringStage) Hit(target int, at time.Time) error {
+	if target < 1 || target > TargetsPerStage {
+		return fmt.Errorf("target %d out of range [1, %d]", target, TargetsPerStage)
+	}
+	if s.Targets[target-1] {
+		return fmt.Errorf("target %d has already been hit on firing range %d", target, s.Range)
+	}
+	s.Targets[target-1] = true
+	s.HitTimes = append(s.HitTimes, at)
+	return nil
+}
+
+// Hits returns the number of targets hit during the stage.
+func (s FiringStage) Hits() int {
+	hits := 0
+	for _, hit := range s.Targets {
+		if hit {
+			hits++
+		}
+	}
+	return hits
+}
+
+// Clone returns a copy of the stage that does not share HitTimes with s.
+func (s FiringStage) Clone() FiringStage {
+	s.HitTimes = slices.Clone(s.HitTimes)
+	return s
+}
+
+// Misses returns the number of targets missed during the stage.
+func (s FiringStage) Misses() int {
+	return TargetsPerStage - s.Hits()
+}
+
+// Card returns the shooting card of the stage, "X" for a hit and "-" for a miss.
+func (s FiringStage) Card() string {
+	var b strings.Builder
+	for _, hit := range s.Targets {
+		if hit {
+			b.WriteByte('X')
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiringStage_Hit(t *testing.T) {
+	stage := FiringStage{Range: 1}
+
+	for _, target := range []int{1, 2, 4} {
+		if err := stage.Hit(target, time.Time{}); err != nil {
+			t.Fatalf("Hit(%d) failed: %v", target, err)
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		target int
+	}{
+		{name: "Duplicate", target: 2},
+		{name: "Zero", target: 0},
+		{name: "TooLarge", target: TargetsPerStage + 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := stage.Hit(tc.target, time.Time{}); err == nil {
+				t.Errorf("Hit(%d): expected error, got nil", tc.target)
+			}
+		})
+	}
+
+	if got := stage.Hits(); got != 3 {
+		t.Errorf("Hits: got %d, want 3", got)
+	}
+	if got := stage.Misses(); got != 2 {
+		t.Errorf("Misses: got %d, want 2", got)
+	}
+	if got := stage.Card(); got != "XX-X-" {
+		t.Errorf("Card: got %q, want %q", got, "XX-X-")
+	}
+}
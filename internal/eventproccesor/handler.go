@@ -9,15 +9,27 @@ import (
 
 // HandleEvent processes a single race event and updates the state of the relevant competitor.
 // It takes the event, a map of all competitors, and the race configuration as input.
-// It returns an error if the event is invalid or cannot be processed.
-func HandleEvent(e *domain.Event, competitors map[int]*domain.Competitor, lapsCount int) error {
+// It returns the outgoing events generated while processing the event,
+// or an error if the event is invalid or cannot be processed.
+func HandleEvent(e *domain.Event, competitors map[int]*domain.Competitor, lapsCount int) ([]domain.Event, error) {
+	params, err := parseParams(e)
+	if err != nil {
+		return nil, err
+	}
+	return handleEvent(e, params, competitors, lapsCount)
+}
+
+// handleEvent is HandleEvent with the comments of the event already parsed.
+func handleEvent(e *domain.Event, params eventParams, competitors map[int]*domain.Competitor, lapsCount int) ([]domain.Event, error) {
 	competitorsID := e.CompetitorID
 	competitor, ok := competitors[competitorsID]
 
 	// For most events, the competitor must already exist(if event is not CompetitorRegistered)
 	if e.ID != domain.EventCompetitorRegistered && !ok {
-		return fmt.Errorf("competitor %d not found", competitorsID)
+		return nil, fmt.Errorf("competitor %d not found", competitorsID)
 	}
+
+	var outgoing []domain.Event
 	switch e.ID {
 	case domain.EventCompetitorRegistered:
 		competitor := domain.NewCompetitor(competitorsID)
@@ -25,11 +37,7 @@ func HandleEvent(e *domain.Event, competitors map[int]*domain.Competitor, lapsCo
 		competitors[competitorsID] = competitor
 
 	case domain.EventStartTimeSet:
-		var err error
-		competitor.ScheduledStart, err = time.Parse("15:04:05.000", e.Comments)
-		if err != nil {
-			return fmt.Errorf("error parsing time: %w", err)
-		}
+		competitor.ScheduledStart = params.startTime
 		competitor.Laps[competitor.CurrentLap].Start = competitor.ScheduledStart
 
 	case domain.EventCompetitorOnStartLine:
@@ -37,19 +45,39 @@ func HandleEvent(e *domain.Event, competitors map[int]*domain.Competitor, lapsCo
 		// No specific state change implemented for this event yet
 	case domain.EventCompetitorStarted:
 		competitor.ActualStart = e.Time
+		competitor.Status = domain.StatusRunning
 	case domain.EventCompetitorOnFiringRange:
+		competitor.FiringStages = append(competitor.FiringStages, domain.FiringStage{
+			Range: params.firingRange,
+			Enter: e.Time,
+		})
 		competitor.FiringCount++
 	case domain.EventTargetHit:
+		stage := competitor.CurrentFiringStage()
+		if stage == nil {
+			return nil, fmt.Errorf("competitor %d is not on a firing range", competitorsID)
+		}
+		if err := stage.Hit(params.target, e.Time); err != nil {
+			return nil, fmt.Errorf("competitor %d: %w", competitorsID, err)
+		}
 		competitor.Shots++
 	case domain.EventCompetitorLeftFiringRange:
-		// Nothing to do
-		// No specific state change implemented for this event yet
+		stage := competitor.CurrentFiringStage()
+		if stage == nil {
+			return nil, fmt.Errorf("competitor %d is not on a firing range", competitorsID)
+		}
+		stage.Exit = e.Time
 	case domain.EventCompetitorEnteredPenalty:
 		competitor.PenaltyLaps = append(competitor.PenaltyLaps, domain.PenaltyLap{
 			Start: e.Time,
 		})
 	case domain.EventCompetitorLeftPenalty:
-		competitor.PenaltyLaps[len(competitor.PenaltyLaps)-1].End = e.Time
+		if len(competitor.PenaltyLaps) == 0 {
+			return nil, fmt.Errorf("competitor %d has not entered the penalty laps", competitorsID)
+		}
+		lap := &competitor.PenaltyLaps[len(competitor.PenaltyLaps)-1]
+		lap.End = e.Time
+		lap.Loops = params.loops
 	case domain.EventCompetitorEndedMainLap:
 		competitor.Laps[competitor.CurrentLap].End = e.Time
 
@@ -59,12 +87,24 @@ func HandleEvent(e *domain.Event, competitors map[int]*domain.Competitor, lapsCo
 			competitor.Laps[competitor.CurrentLap].Start = e.Time
 		} else {
 			competitor.Status = domain.StatusFinished
+			lap := competitor.Laps[competitor.CurrentLap]
+			outgoing = append(outgoing, domain.Event{
+				Time:         e.Time,
+				ID:           domain.EventCompetitorFinished,
+				CompetitorID: competitorsID,
+				Comments:     formatDuration(lap.End.Sub(lap.Start)),
+			})
 		}
 	case domain.EventCompetitorCanNotContinue:
 		competitor.Status = domain.StatusNotFinished
 	default:
-		return fmt.Errorf("unknown event %d", e.ID)
+		return nil, fmt.Errorf("unknown event %d", e.ID)
 	}
 
-	return nil
+	return outgoing, nil
+}
+
+// formatDuration formats a time.Duration into a "HH:MM:SS.mmm" string, as used in the output log.
+func formatDuration(d time.Duration) string {
+	return time.Time{}.Add(d).Format("15:04:05.000")
 }
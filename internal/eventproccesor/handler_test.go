@@ -23,7 +23,7 @@ func TestHandleEvent(t *testing.T) {
 		competitors := make(map[int]*domain.Competitor)
 		event := &domain.Event{Time: baseTime, ID: domain.EventCompetitorRegistered, CompetitorID: 1}
 
-		err := HandleEvent(event, competitors, lapsCount)
+		_, err := HandleEvent(event, competitors, lapsCount)
 		if err != nil {
 			t.Fatalf("HandleEvent failed: %v", err)
 		}
@@ -47,7 +47,7 @@ func TestHandleEvent(t *testing.T) {
 		expectedScheduledTime, _ := time.Parse("15:04:05.000", scheduledStartTimeStr)
 
 		event := &domain.Event{Time: baseTime, ID: domain.EventStartTimeSet, CompetitorID: 1, Comments: scheduledStartTimeStr}
-		err := HandleEvent(event, competitors, lapsCount)
+		_, err := HandleEvent(event, competitors, lapsCount)
 		if err != nil {
 			t.Fatalf("HandleEvent failed: %v", err)
 		}
@@ -58,4 +58,67 @@ func TestHandleEvent(t *testing.T) {
 			t.Errorf("ScheduledStart mismatch: got %s, want %s", got, want)
 		}
 	})
+
+	t.Run("EventCompetitorEndedMainLap_Finished", func(t *testing.T) {
+		competitors := make(map[int]*domain.Competitor)
+		competitors[1] = newTestCompetitor(1)
+		competitors[1].CurrentLap = lapsCount - 1
+		competitors[1].Laps[lapsCount-1].Start = baseTime
+
+		endTime := baseTime.Add(12*time.Minute + 38*time.Second + 610*time.Millisecond)
+		event := &domain.Event{Time: endTime, ID: domain.EventCompetitorEndedMainLap, CompetitorID: 1}
+		outgoing, err := HandleEvent(event, competitors, lapsCount)
+		if err != nil {
+			t.Fatalf("HandleEvent failed: %v", err)
+		}
+
+		if competitors[1].Status != domain.StatusFinished {
+			t.Errorf("Competitor status mismatch: got %v, want %v", competitors[1].Status, domain.StatusFinished)
+		}
+		if len(outgoing) != 1 {
+			t.Fatalf("Outgoing events length: got %d, want 1", len(outgoing))
+		}
+		want := domain.Event{Time: endTime, ID: domain.EventCompetitorFinished, CompetitorID: 1, Comments: "00:12:38.610"}
+		if outgoing[0] != want {
+			t.Errorf("Outgoing event mismatch: got %+v, want %+v", outgoing[0], want)
+		}
+	})
+
+	t.Run("EventTargetHit", func(t *testing.T) {
+		competitors := make(map[int]*domain.Competitor)
+		competitors[1] = newTestCompetitor(1)
+
+		events := []*domain.Event{
+			{Time: baseTime, ID: domain.EventCompetitorOnFiringRange, CompetitorID: 1, Comments: "2"},
+			{Time: baseTime.Add(time.Second), ID: domain.EventTargetHit, CompetitorID: 1, Comments: "1"},
+			{Time: baseTime.Add(2 * time.Second), ID: domain.EventTargetHit, CompetitorID: 1, Comments: "4"},
+		}
+		for _, e := range events {
+			if _, err := HandleEvent(e, competitors, lapsCount); err != nil {
+				t.Fatalf("HandleEvent failed: %v", err)
+			}
+		}
+
+		duplicate := &domain.Event{Time: baseTime.Add(3 * time.Second), ID: domain.EventTargetHit, CompetitorID: 1, Comments: "4"}
+		if _, err := HandleEvent(duplicate, competitors, lapsCount); err == nil {
+			t.Errorf("HandleEvent: expected error for duplicate hit, got nil")
+		}
+
+		if len(competitors[1].FiringStages) != 1 {
+			t.Fatalf("FiringStages length: got %d, want 1", len(competitors[1].FiringStages))
+		}
+		stage := competitors[1].FiringStages[0]
+		if stage.Range != 2 {
+			t.Errorf("Range: got %d, want 2", stage.Range)
+		}
+		if stage.Card() != "X--X-" {
+			t.Errorf("Card: got %q, want %q", stage.Card(), "X--X-")
+		}
+		if len(stage.HitTimes) != 2 {
+			t.Errorf("HitTimes length: got %d, want 2", len(stage.HitTimes))
+		}
+		if competitors[1].Shots != 2 {
+			t.Errorf("Shots: got %d, want 2", competitors[1].Shots)
+		}
+	})
 }
@@ -0,0 +1,54 @@
+package eventproccesor
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// eventParams holds the values carried in the comments of an event.
+// The comments are parsed once by parseParams and shared by the validator and the handler.
+type eventParams struct {
+	startTime   time.Time // Scheduled start of EventStartTimeSet
+	firingRange int       // Firing range of EventCompetitorOnFiringRange
+	target      int       // Target of EventTargetHit
+	loops       int       // Penalty loops of EventCompetitorLeftPenalty, 0 if not reported
+}
+
+// parseParams parses the comments of the event.
+// Events without parameters are not checked, their comments are free text.
+func parseParams(e *domain.Event) (eventParams, error) {
+	var params eventParams
+	switch e.ID {
+	case domain.EventStartTimeSet:
+		startTime, err := time.Parse("15:04:05.000", e.Comments)
+		if err != nil {
+			return params, fmt.Errorf("invalid start time %q", e.Comments)
+		}
+		params.startTime = startTime
+	case domain.EventCompetitorOnFiringRange:
+		firingRange, err := strconv.Atoi(e.Comments)
+		if err != nil || firingRange < 1 {
+			return params, fmt.Errorf("invalid firing range %q", e.Comments)
+		}
+		params.firingRange = firingRange
+	case domain.EventTargetHit:
+		target, err := strconv.Atoi(e.Comments)
+		if err != nil || target < 1 || target > domain.TargetsPerStage {
+			return params, fmt.Errorf("invalid target %q", e.Comments)
+		}
+		params.target = target
+	case domain.EventCompetitorLeftPenalty:
+		if e.Comments == "" {
+			break
+		}
+		loops, err := strconv.Atoi(e.Comments)
+		if err != nil || loops < 1 {
+			return params, fmt.Errorf("invalid penalty loops count %q", e.Comments)
+		}
+		params.loops = loops
+	}
+	return params, nil
+}
@@ -0,0 +1,178 @@
+package eventproccesor
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// Processor handles the event stream and keeps a race clock driven by event times.
+// The clock is used to disqualify competitors as soon as their start interval has elapsed.
+// In the mass start all competitors start at the start time of the config and nobody is disqualified.
+type Processor struct {
+	cfg         *config.Config
+	competitors map[int]*domain.Competitor
+	validator   *Validator
+	now         time.Time
+	massStart   bool
+	registry    Registry
+}
+
+// Registry provides the start list data of competitors.
+type Registry interface {
+	// Athlete returns the athlete data of the competitor and whether the competitor is in the start list.
+	Athlete(id int) (domain.Athlete, bool)
+}
+
+// NewProcessor creates a Processor that updates the given map of competitors.
+func NewProcessor(cfg *config.Config, competitors map[int]*domain.Competitor) *Processor {
+	massStart := cfg.Format == config.RaceMassStart
+	validator := NewValidator(cfg.Laps)
+	validator.massStart = massStart
+	return &Processor{
+		cfg:         cfg,
+		competitors: competitors,
+		validator:   validator,
+		massStart:   massStart,
+	}
+}
+
+// UseRegistry sets the start list of the race, it must be called before the first event.
+// Events for competitors missing from the start list are invalid,
+// registered competitors get their athlete data.
+func (p *Processor) UseRegistry(registry Registry) {
+	p.registry = registry
+}
+
+// Validate checks that the event is legal in the current state of the race.
+// It must be called before Advance and Handle, an invalid event must not be handled.
+// The returned Transition holds a copy of the event with its parsed comments and is passed to Handle.
+// The validation state is advanced only when Handle succeeds, so a failed event can be retried.
+func (p *Processor) Validate(e *domain.Event) (Transition, error) {
+	if p.registry != nil && e.ID != domain.EventRaceClosed {
+		if _, ok := p.registry.Athlete(e.CompetitorID); !ok {
+			return Transition{}, fmt.Errorf("%w: event %d for competitor(%d): not in the start list", ErrInvalidEvent, e.ID, e.CompetitorID)
+		}
+	}
+	params, err := parseParams(e)
+	if err != nil {
+		return Transition{}, fmt.Errorf("%w: event %d for competitor(%d): %v", ErrInvalidEvent, e.ID, e.CompetitorID, err)
+	}
+	return p.validator.check(e, params)
+}
+
+// Advance moves the race clock forward to the given time.
+// It returns disqualification events for competitors whose start interval
+// has elapsed before that time, ordered by time.
+func (p *Processor) Advance(now time.Time) []domain.Event {
+	// Event times are parsed without a date, so they precede the zero time.Time
+	if p.now.IsZero() || now.After(p.now) {
+		p.now = now
+	}
+	return p.disqualify(func(deadline time.Time) bool {
+		return deadline.Before(p.now)
+	})
+}
+
+// Handle processes a single incoming event validated by Validate.
+// Events for disqualified competitors are accepted but do not change their state.
+// It returns the outgoing events generated at the time of the event.
+func (p *Processor) Handle(t Transition) ([]domain.Event, error) {
+	outgoing, err := p.handle(&t.event, t.params)
+	if err != nil {
+		return nil, err
+	}
+	p.validator.commit(t)
+	return outgoing, nil
+}
+
+// handle applies the event with its parsed comments to the competitors.
+func (p *Processor) handle(e *domain.Event, params eventParams) ([]domain.Event, error) {
+	if e.ID == domain.EventRaceClosed {
+		// The sentinel event does not change the state of competitors
+		return nil, nil
+	}
+	if c, ok := p.competitors[e.CompetitorID]; ok && c.Disqualified {
+		return nil, nil
+	}
+
+	outgoing, err := handleEvent(e, params, p.competitors, p.cfg.Laps)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case e.ID == domain.EventCompetitorLeftPenalty:
+		p.estimatePenaltyLoops(p.competitors[e.CompetitorID])
+	case e.ID == domain.EventCompetitorRegistered:
+		c := p.competitors[e.CompetitorID]
+		if p.registry != nil {
+			c.Athlete, _ = p.registry.Athlete(c.ID)
+		}
+		if p.massStart {
+			c.ScheduledStart = p.cfg.StartTime
+		}
+	}
+	return outgoing, nil
+}
+
+// estimatePenaltyLoops estimates the number of loops of the last visit of the penalty area
+// from its duration when the count has not been reported explicitly.
+// Nothing is estimated if the expected loop time is not configured.
+func (p *Processor) estimatePenaltyLoops(c *domain.Competitor) {
+	lap := &c.PenaltyLaps[len(c.PenaltyLaps)-1]
+	if lap.Loops > 0 || p.cfg.PenaltyLoopTime <= 0 {
+		return
+	}
+	// Entering the penalty area means skiing at least one loop
+	lap.Loops = max(1, int(math.Round(float64(lap.End.Sub(lap.Start))/float64(p.cfg.PenaltyLoopTime))))
+}
+
+// Flush is called at the end of the event stream.
+// It disqualifies all competitors who have a scheduled start but never started,
+// and marks competitors who are still on the course as not finished.
+func (p *Processor) Flush() []domain.Event {
+	for _, c := range p.competitors {
+		if c.Status == domain.StatusRunning {
+			c.Status = domain.StatusNotFinished
+		}
+	}
+	return p.disqualify(func(time.Time) bool { return true })
+}
+
+// disqualify marks as disqualified every competitor who has not started
+// and whose start deadline satisfies the expired predicate.
+// There is no start interval in the mass start, so nobody is disqualified.
+func (p *Processor) disqualify(expired func(deadline time.Time) bool) []domain.Event {
+	if p.massStart {
+		return nil
+	}
+	var events []domain.Event
+	for _, c := range p.competitors {
+		if c.Disqualified || c.ScheduledStart.IsZero() || !c.ActualStart.IsZero() {
+			continue
+		}
+		deadline := c.ScheduledStart.Add(p.cfg.StartDelta)
+		if !expired(deadline) {
+			continue
+		}
+		c.Disqualified = true
+		c.Status = domain.StatusNotStarted
+		events = append(events, domain.Event{
+			Time:         deadline,
+			ID:           domain.EventCompetitorDisqualified,
+			CompetitorID: c.ID,
+		})
+	}
+
+	slices.SortFunc(events, func(a, b domain.Event) int {
+		if c := a.Time.Compare(b.Time); c != 0 {
+			return c
+		}
+		return a.CompetitorID - b.CompetitorID
+	})
+	return events
+}
@@ -0,0 +1,291 @@
+package eventproccesor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// process validates and handles the event the way the race does.
+func process(p *Processor, e *domain.Event) ([]domain.Event, error) {
+	t, err := p.Validate(e)
+	if err != nil {
+		return nil, err
+	}
+	p.Advance(e.Time)
+	return p.Handle(t)
+}
+
+func TestProcessor(t *testing.T) {
+	cfg := &config.Config{Laps: 2, StartDelta: 30 * time.Second}
+	start, _ := time.Parse("15:04:05.000", "10:00:00.000")
+
+	newProcessor := func() (*Processor, map[int]*domain.Competitor) {
+		competitors := make(map[int]*domain.Competitor)
+		p := NewProcessor(cfg, competitors)
+		for id := 1; id <= 2; id++ {
+			events := []*domain.Event{
+				{Time: start.Add(-time.Hour), ID: domain.EventCompetitorRegistered, CompetitorID: id},
+				{Time: start.Add(-time.Hour), ID: domain.EventStartTimeSet, CompetitorID: id, Comments: start.Add(time.Duration(id-1) * time.Minute).Format("15:04:05.000")},
+			}
+			for _, e := range events {
+				if _, err := process(p, e); err != nil {
+					t.Fatalf("Process failed: %v", err)
+				}
+			}
+		}
+		return p, competitors
+	}
+
+	t.Run("DisqualifiedWhenStartIntervalElapsed", func(t *testing.T) {
+		p, competitors := newProcessor()
+
+		if got := p.Advance(start.Add(30 * time.Second)); len(got) != 0 {
+			t.Fatalf("Advance to deadline: got %d events, want 0", len(got))
+		}
+
+		got := p.Advance(start.Add(31 * time.Second))
+		if len(got) != 1 {
+			t.Fatalf("Advance past deadline: got %d events, want 1", len(got))
+		}
+		want := domain.Event{Time: start.Add(30 * time.Second), ID: domain.EventCompetitorDisqualified, CompetitorID: 1}
+		if got[0] != want {
+			t.Errorf("Disqualification event mismatch: got %+v, want %+v", got[0], want)
+		}
+		if !competitors[1].Disqualified || competitors[1].Status != domain.StatusNotStarted {
+			t.Errorf("Competitor 1 must be disqualified, got %+v", competitors[1])
+		}
+
+		// A late start does not change the state of a disqualified competitor
+		if _, err := process(p, &domain.Event{Time: start.Add(31 * time.Second), ID: domain.EventCompetitorStarted, CompetitorID: 1}); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		if !competitors[1].ActualStart.IsZero() {
+			t.Errorf("ActualStart of disqualified competitor changed: %v", competitors[1].ActualStart)
+		}
+	})
+
+	t.Run("FlushDisqualifiesNotStarted", func(t *testing.T) {
+		p, _ := newProcessor()
+
+		if _, err := process(p, &domain.Event{Time: start, ID: domain.EventCompetitorStarted, CompetitorID: 1}); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+
+		got := p.Flush()
+		if len(got) != 1 || got[0].CompetitorID != 2 {
+			t.Fatalf("Flush: got %+v, want disqualification of competitor 2", got)
+		}
+	})
+
+	t.Run("FlushMarksStartedNotFinished", func(t *testing.T) {
+		p, competitors := newProcessor()
+
+		if _, err := process(p, &domain.Event{Time: start, ID: domain.EventCompetitorStarted, CompetitorID: 1}); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		if competitors[1].Status != domain.StatusRunning {
+			t.Fatalf("Status after start: got %s, want %s", competitors[1].Status, domain.StatusRunning)
+		}
+
+		// A competitor still on the course when the events end has not finished
+		p.Flush()
+		if competitors[1].Status != domain.StatusNotFinished {
+			t.Errorf("Status after Flush: got %s, want %s", competitors[1].Status, domain.StatusNotFinished)
+		}
+	})
+}
+
+func TestProcessor_PenaltyLoops(t *testing.T) {
+	cfg := &config.Config{Laps: 1, StartDelta: 30 * time.Second, PenaltyLoopTime: 30 * time.Second}
+	start, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	competitors := make(map[int]*domain.Competitor)
+	p := NewProcessor(cfg, competitors)
+	events := []*domain.Event{
+		{Time: at(-time.Hour), ID: domain.EventCompetitorRegistered, CompetitorID: 1},
+		{Time: at(-time.Hour), ID: domain.EventStartTimeSet, CompetitorID: 1, Comments: "10:00:00.000"},
+		{Time: at(0), ID: domain.EventCompetitorStarted, CompetitorID: 1},
+		// Estimated from the duration: 70s is closer to two loops of 30s
+		{Time: at(time.Minute), ID: domain.EventCompetitorEnteredPenalty, CompetitorID: 1},
+		{Time: at(time.Minute + 70*time.Second), ID: domain.EventCompetitorLeftPenalty, CompetitorID: 1},
+		// A short visit still counts as one loop
+		{Time: at(3 * time.Minute), ID: domain.EventCompetitorEnteredPenalty, CompetitorID: 1},
+		{Time: at(3*time.Minute + 5*time.Second), ID: domain.EventCompetitorLeftPenalty, CompetitorID: 1},
+		// The explicit count wins over the estimation
+		{Time: at(5 * time.Minute), ID: domain.EventCompetitorEnteredPenalty, CompetitorID: 1},
+		{Time: at(5*time.Minute + 30*time.Second), ID: domain.EventCompetitorLeftPenalty, CompetitorID: 1, Comments: "3"},
+	}
+	for _, e := range events {
+		if _, err := process(p, e); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+
+	var got []int
+	for _, lap := range competitors[1].PenaltyLaps {
+		got = append(got, lap.Loops)
+	}
+	if len(got) != 3 || got[0] != 2 || got[1] != 1 || got[2] != 3 {
+		t.Errorf("Loops: got %v, want [2 1 3]", got)
+	}
+
+	invalid := &domain.Event{Time: at(6 * time.Minute), ID: domain.EventCompetitorLeftPenalty, CompetitorID: 1, Comments: "0"}
+	entered := &domain.Event{Time: at(6 * time.Minute), ID: domain.EventCompetitorEnteredPenalty, CompetitorID: 1}
+	if _, err := process(p, entered); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if _, err := p.Validate(invalid); err == nil {
+		t.Errorf("Validate: expected error for loops count %q", invalid.Comments)
+	}
+}
+
+func TestProcessor_MassStart(t *testing.T) {
+	start, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	cfg := &config.Config{Laps: 1, Format: config.RaceMassStart, StartTime: start}
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	competitors := make(map[int]*domain.Competitor)
+	p := NewProcessor(cfg, competitors)
+	events := []*domain.Event{
+		{Time: at(-time.Hour), ID: domain.EventCompetitorRegistered, CompetitorID: 1},
+		{Time: at(-time.Hour), ID: domain.EventCompetitorRegistered, CompetitorID: 2},
+		{Time: at(-time.Minute), ID: domain.EventCompetitorOnStartLine, CompetitorID: 1},
+		{Time: at(0), ID: domain.EventCompetitorStarted, CompetitorID: 1},
+		// Competitor 2 starts late without being disqualified
+		{Time: at(10 * time.Minute), ID: domain.EventCompetitorStarted, CompetitorID: 2},
+		{Time: at(25 * time.Minute), ID: domain.EventCompetitorEndedMainLap, CompetitorID: 2},
+	}
+	for _, e := range events {
+		tr, err := p.Validate(e)
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		if got := p.Advance(e.Time); len(got) != 0 {
+			t.Fatalf("Advance: got %+v, want no disqualifications", got)
+		}
+		if _, err := p.Handle(tr); err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+	}
+
+	for id, c := range competitors {
+		if !c.ScheduledStart.Equal(start) {
+			t.Errorf("Competitor %d: ScheduledStart got %v, want the common start time", id, c.ScheduledStart)
+		}
+	}
+	if competitors[2].Status != domain.StatusFinished || competitors[2].Disqualified {
+		t.Errorf("Competitor 2 must be finished, got %+v", competitors[2])
+	}
+
+	draw := &domain.Event{Time: at(26 * time.Minute), ID: domain.EventStartTimeSet, CompetitorID: 1, Comments: "10:30:00.000"}
+	if _, err := p.Validate(draw); err == nil {
+		t.Errorf("Validate: expected error for a draw in the mass start")
+	}
+	if got := p.Flush(); len(got) != 0 {
+		t.Errorf("Flush: got %+v, want no disqualifications", got)
+	}
+	if competitors[1].Status != domain.StatusNotFinished {
+		t.Errorf("Competitor 1: Status got %v, want NotFinished", competitors[1].Status)
+	}
+}
+
+type testRegistry map[int]domain.Athlete
+
+func (r testRegistry) Athlete(id int) (domain.Athlete, bool) {
+	a, ok := r[id]
+	return a, ok
+}
+
+func TestProcessor_Registry(t *testing.T) {
+	cfg := &config.Config{Laps: 1, StartDelta: 30 * time.Second}
+	at, _ := time.Parse("15:04:05.000", "09:00:00.000")
+
+	competitors := make(map[int]*domain.Competitor)
+	p := NewProcessor(cfg, competitors)
+	anna := domain.Athlete{Bib: 11, Name: "Anna Berg", Nation: "SWE"}
+	p.UseRegistry(testRegistry{1: anna})
+
+	registered := &domain.Event{Time: at, ID: domain.EventCompetitorRegistered, CompetitorID: 1}
+	if _, err := process(p, registered); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if competitors[1].Athlete != anna {
+		t.Errorf("Athlete: got %+v, want %+v", competitors[1].Athlete, anna)
+	}
+
+	unknown := &domain.Event{Time: at, ID: domain.EventCompetitorRegistered, CompetitorID: 2}
+	if _, err := p.Validate(unknown); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Validate: got %v, want ErrInvalidEvent for a competitor missing from the start list", err)
+	}
+}
+
+// TestProcessor_ValidateCommitsAfterHandle checks that a validated event which fails to be handled
+// does not advance the validation state, so the event can be retried.
+func TestProcessor_ValidateCommitsAfterHandle(t *testing.T) {
+	cfg := &config.Config{Laps: 1, StartDelta: 30 * time.Second}
+	start, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	competitors := make(map[int]*domain.Competitor)
+	p := NewProcessor(cfg, competitors)
+	for _, e := range []*domain.Event{
+		{Time: at(-time.Hour), ID: domain.EventCompetitorRegistered, CompetitorID: 1},
+		{Time: at(-time.Hour), ID: domain.EventStartTimeSet, CompetitorID: 1, Comments: "10:00:00.000"},
+		{Time: at(0), ID: domain.EventCompetitorStarted, CompetitorID: 1},
+		{Time: at(time.Minute), ID: domain.EventCompetitorOnFiringRange, CompetitorID: 1, Comments: "1"},
+	} {
+		if _, err := process(p, e); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+
+	// The handler fails while the competitor has no open firing stage
+	stages := competitors[1].FiringStages
+	competitors[1].FiringStages = nil
+	hit := &domain.Event{Time: at(time.Minute + time.Second), ID: domain.EventTargetHit, CompetitorID: 1, Comments: "1"}
+	if _, err := process(p, hit); err == nil {
+		t.Fatal("Expected Handle to fail without a firing stage")
+	}
+
+	competitors[1].FiringStages = stages
+	retry := *hit
+	if _, err := process(p, &retry); err != nil {
+		t.Fatalf("Retry of the failed event: %v", err)
+	}
+	if competitors[1].Shots != 1 {
+		t.Errorf("Shots: got %d, want 1", competitors[1].Shots)
+	}
+}
+
+// TestProcessor_HandleTransition checks that Handle applies the validated event
+// even if the caller reuses the event between Validate and Handle.
+func TestProcessor_HandleTransition(t *testing.T) {
+	cfg := &config.Config{Laps: 1, StartDelta: 30 * time.Second}
+	at, _ := time.Parse("15:04:05.000", "09:00:00.000")
+
+	competitors := make(map[int]*domain.Competitor)
+	p := NewProcessor(cfg, competitors)
+
+	e := &domain.Event{Time: at, ID: domain.EventCompetitorRegistered, CompetitorID: 1}
+	tr, err := p.Validate(e)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	*e = domain.Event{Time: at, ID: domain.EventStartTimeSet, CompetitorID: 1, Comments: "10:00:00.000"}
+	if _, err := p.Handle(tr); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if _, ok := competitors[1]; !ok {
+		t.Fatal("Competitor 1 must be registered")
+	}
+
+	// The registration has advanced the validation state, the draw is legal now
+	if _, err := process(p, e); err != nil {
+		t.Errorf("Process of the draw failed: %v", err)
+	}
+}
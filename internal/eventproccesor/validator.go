@@ -0,0 +1,235 @@
+package eventproccesor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// ErrInvalidEvent is returned when an event violates the order of the race.
+var ErrInvalidEvent = errors.New("invalid event")
+
+// competitorState is a state of a competitor in the validation state machine.
+type competitorState int
+
+const (
+	stateUnknown competitorState = iota
+	stateRegistered
+	stateStartTimeSet
+	stateOnStartLine
+	stateOnCourse
+	stateOnFiringRange
+	stateInPenalty
+	stateFinished
+	stateNotFinished
+)
+
+func (s competitorState) String() string {
+	switch s {
+	case stateUnknown:
+		return "not registered"
+	case stateRegistered:
+		return "registered"
+	case stateStartTimeSet:
+		return "start time set"
+	case stateOnStartLine:
+		return "on the start line"
+	case stateOnCourse:
+		return "on the course"
+	case stateOnFiringRange:
+		return "on the firing range"
+	case stateInPenalty:
+		return "on the penalty laps"
+	case stateFinished:
+		return "finished"
+	case stateNotFinished:
+		return "not finished"
+	default:
+		return fmt.Sprintf("state(%d)", int(s))
+	}
+}
+
+// transitions defines the legal transitions of the state machine.
+// Transitions which depend on the competitor data are resolved in Validator.next.
+var transitions = map[domain.EventID]map[competitorState]competitorState{
+	domain.EventCompetitorRegistered: {
+		stateUnknown: stateRegistered,
+	},
+	domain.EventStartTimeSet: {
+		stateRegistered:   stateStartTimeSet,
+		stateStartTimeSet: stateStartTimeSet,
+	},
+	domain.EventCompetitorOnStartLine: {
+		stateStartTimeSet: stateOnStartLine,
+	},
+	domain.EventCompetitorStarted: {
+		stateStartTimeSet: stateOnCourse,
+		stateOnStartLine:  stateOnCourse,
+	},
+	domain.EventCompetitorOnFiringRange: {
+		stateOnCourse: stateOnFiringRange,
+	},
+	domain.EventTargetHit: {
+		stateOnFiringRange: stateOnFiringRange,
+	},
+	domain.EventCompetitorLeftFiringRange: {
+		stateOnFiringRange: stateOnCourse,
+	},
+	domain.EventCompetitorEnteredPenalty: {
+		stateOnCourse: stateInPenalty,
+	},
+	domain.EventCompetitorLeftPenalty: {
+		stateInPenalty: stateOnCourse,
+	},
+	domain.EventCompetitorEndedMainLap: {
+		stateOnCourse: stateOnCourse,
+	},
+	domain.EventCompetitorCanNotContinue: {
+		stateRegistered:    stateNotFinished,
+		stateStartTimeSet:  stateNotFinished,
+		stateOnStartLine:   stateNotFinished,
+		stateOnCourse:      stateNotFinished,
+		stateOnFiringRange: stateNotFinished,
+		stateInPenalty:     stateNotFinished,
+	},
+}
+
+// competitorProgress holds the validation data of a single competitor.
+type competitorProgress struct {
+	state   competitorState
+	laps    int
+	targets [domain.TargetsPerStage]bool
+}
+
+// Validator checks that events form a legal sequence for every competitor
+// and that event times do not go backwards.
+type Validator struct {
+	lapsCount int
+	// massStart allows to start without a draw and forbids drawing start times
+	massStart   bool
+	last        time.Time
+	competitors map[int]*competitorProgress
+}
+
+// NewValidator creates a Validator for a race with the given number of laps.
+func NewValidator(lapsCount int) *Validator {
+	return &Validator{
+		lapsCount:   lapsCount,
+		competitors: make(map[int]*competitorProgress),
+	}
+}
+
+// Validate checks the event against the current state of the competitor.
+// If the event is legal, the state is advanced, otherwise the state is left unchanged
+// and an error wrapping ErrInvalidEvent is returned.
+func (v *Validator) Validate(e *domain.Event) error {
+	params, err := parseParams(e)
+	if err != nil {
+		return fmt.Errorf("%w: event %d for competitor(%d): %v", ErrInvalidEvent, e.ID, e.CompetitorID, err)
+	}
+	t, err := v.check(e, params)
+	if err != nil {
+		return err
+	}
+	v.commit(t)
+	return nil
+}
+
+// Transition is a legal event with its parsed comments and the state it moves the competitor to.
+// It is returned by Processor.Validate and applied by Processor.Handle.
+type Transition struct {
+	event  domain.Event
+	params eventParams
+	next   competitorState
+}
+
+// check checks the event without changing the state, the returned transition is applied by commit.
+func (v *Validator) check(e *domain.Event, params eventParams) (Transition, error) {
+	if !v.last.IsZero() && e.Time.Before(v.last) {
+		return Transition{}, fmt.Errorf("%w: time %s is before the previous event time %s",
+			ErrInvalidEvent, e.Time.Format("15:04:05.000"), v.last.Format("15:04:05.000"))
+	}
+
+	t := Transition{event: *e, params: params}
+	if e.ID == domain.EventRaceClosed {
+		return t, nil
+	}
+
+	p, ok := v.competitors[e.CompetitorID]
+	if !ok {
+		p = &competitorProgress{}
+	}
+
+	next, err := v.next(e, params, p)
+	if err != nil {
+		return Transition{}, fmt.Errorf("%w: event %d for competitor(%d): %v", ErrInvalidEvent, e.ID, e.CompetitorID, err)
+	}
+	t.next = next
+	return t, nil
+}
+
+// commit advances the state by a transition returned by check.
+func (v *Validator) commit(t Transition) {
+	e := &t.event
+	v.last = e.Time
+	if e.ID == domain.EventRaceClosed {
+		return
+	}
+
+	p, ok := v.competitors[e.CompetitorID]
+	if !ok {
+		p = &competitorProgress{}
+		v.competitors[e.CompetitorID] = p
+	}
+	v.apply(e, t.params, p, t.next)
+}
+
+// next returns the state of the competitor after the event without changing it.
+func (v *Validator) next(e *domain.Event, params eventParams, p *competitorProgress) (competitorState, error) {
+	byState, ok := transitions[e.ID]
+	if !ok {
+		return 0, fmt.Errorf("unknown event")
+	}
+	state := p.state
+	if v.massStart {
+		switch {
+		case e.ID == domain.EventStartTimeSet:
+			return 0, fmt.Errorf("start times are not drawn in the mass start")
+		case state == stateRegistered:
+			// Registered competitors are ready to start at the common start time
+			state = stateStartTimeSet
+		}
+	}
+	next, ok := byState[state]
+	if !ok {
+		return 0, fmt.Errorf("not allowed when the competitor is %s", p.state)
+	}
+
+	switch e.ID {
+	case domain.EventTargetHit:
+		if p.targets[params.target-1] {
+			return 0, fmt.Errorf("target %d has already been hit", params.target)
+		}
+	case domain.EventCompetitorEndedMainLap:
+		if p.laps+1 >= v.lapsCount {
+			next = stateFinished
+		}
+	}
+
+	return next, nil
+}
+
+// apply advances the competitor to the next state.
+func (v *Validator) apply(e *domain.Event, params eventParams, p *competitorProgress, next competitorState) {
+	switch e.ID {
+	case domain.EventCompetitorOnFiringRange:
+		p.targets = [domain.TargetsPerStage]bool{}
+	case domain.EventTargetHit:
+		p.targets[params.target-1] = true
+	case domain.EventCompetitorEndedMainLap:
+		p.laps++
+	}
+	p.state = next
+}
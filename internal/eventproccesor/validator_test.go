@@ -0,0 +1,90 @@
+package eventproccesor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestValidator(t *testing.T) {
+	baseTime := time.Date(2025, 6, 6, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return baseTime.Add(time.Duration(seconds) * time.Second) }
+
+	// A legal sequence of events for a race with a single lap
+	race := []domain.Event{
+		{Time: at(0), ID: domain.EventCompetitorRegistered, CompetitorID: 1},
+		{Time: at(1), ID: domain.EventStartTimeSet, CompetitorID: 1, Comments: "10:05:00.000"},
+		{Time: at(2), ID: domain.EventCompetitorOnStartLine, CompetitorID: 1},
+		{Time: at(3), ID: domain.EventCompetitorStarted, CompetitorID: 1},
+		{Time: at(4), ID: domain.EventCompetitorOnFiringRange, CompetitorID: 1, Comments: "1"},
+		{Time: at(5), ID: domain.EventTargetHit, CompetitorID: 1, Comments: "1"},
+		{Time: at(6), ID: domain.EventCompetitorLeftFiringRange, CompetitorID: 1},
+		{Time: at(7), ID: domain.EventCompetitorEnteredPenalty, CompetitorID: 1},
+		{Time: at(8), ID: domain.EventCompetitorLeftPenalty, CompetitorID: 1},
+		{Time: at(9), ID: domain.EventCompetitorEndedMainLap, CompetitorID: 1},
+	}
+
+	t.Run("LegalSequence", func(t *testing.T) {
+		v := NewValidator(1)
+		for i := range race {
+			if err := v.Validate(&race[i]); err != nil {
+				t.Fatalf("Validate(event %d) failed: %v", i, err)
+			}
+		}
+	})
+
+	testCases := []struct {
+		name  string
+		valid int // number of legal events from race before the invalid one
+		event domain.Event
+	}{
+		{
+			name:  "StartedBeforeStartTimeSet",
+			valid: 1,
+			event: domain.Event{Time: at(1), ID: domain.EventCompetitorStarted, CompetitorID: 1},
+		},
+		{
+			name:  "LeftPenaltyWithoutEntering",
+			valid: 4,
+			event: domain.Event{Time: at(4), ID: domain.EventCompetitorLeftPenalty, CompetitorID: 1},
+		},
+		{
+			name:  "TimeGoesBackwards",
+			valid: 4,
+			event: domain.Event{Time: at(2), ID: domain.EventCompetitorOnFiringRange, CompetitorID: 1, Comments: "1"},
+		},
+		{
+			name:  "DuplicateHit",
+			valid: 6,
+			event: domain.Event{Time: at(6), ID: domain.EventTargetHit, CompetitorID: 1, Comments: "1"},
+		},
+		{
+			name:  "EventAfterFinish",
+			valid: len(race),
+			event: domain.Event{Time: at(10), ID: domain.EventCompetitorOnFiringRange, CompetitorID: 1, Comments: "1"},
+		},
+		{
+			name:  "NotRegistered",
+			valid: 0,
+			event: domain.Event{Time: at(0), ID: domain.EventStartTimeSet, CompetitorID: 2, Comments: "10:05:00.000"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(1)
+			for i := range race[:tc.valid] {
+				if err := v.Validate(&race[i]); err != nil {
+					t.Fatalf("Validate(event %d) failed: %v", i, err)
+				}
+			}
+
+			err := v.Validate(&tc.event)
+			if !errors.Is(err, ErrInvalidEvent) {
+				t.Errorf("Validate: got %v, want ErrInvalidEvent", err)
+			}
+		})
+	}
+}
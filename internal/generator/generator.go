@@ -0,0 +1,240 @@
+// Package generator produces synthetic races: valid event streams together with the results they must lead to.
+package generator
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+)
+
+// Params are the parameters of a synthetic race.
+type Params struct {
+	Competitors int
+	// Skiing speed of a competitor is drawn from the normal distribution, in m/s.
+	SpeedMean   float64
+	SpeedStdDev float64
+	// HitProbability is the probability to hit a single target.
+	HitProbability float64
+	// DNSRate and DNFRate are the shares of competitors who do not start and do not finish.
+	DNSRate float64
+	DNFRate float64
+	Seed    uint64
+}
+
+// DefaultParams returns parameters resembling the sample race.
+func DefaultParams() Params {
+	return Params{
+		Competitors:    50,
+		SpeedMean:      4.5,
+		SpeedStdDev:    0.3,
+		HitProbability: 0.8,
+		DNSRate:        0.02,
+		DNFRate:        0.03,
+		Seed:           1,
+	}
+}
+
+// Validate checks the parameters.
+func (p Params) Validate() error {
+	var errs []error
+	if p.Competitors <= 0 {
+		errs = append(errs, fmt.Errorf("competitors must be positive, got %d", p.Competitors))
+	}
+	if p.SpeedMean <= 0 || p.SpeedStdDev < 0 {
+		errs = append(errs, fmt.Errorf("invalid speed distribution %.2f±%.2f", p.SpeedMean, p.SpeedStdDev))
+	}
+	for name, v := range map[string]float64{"hit probability": p.HitProbability, "DNS rate": p.DNSRate, "DNF rate": p.DNFRate} {
+		if v < 0 || v > 1 {
+			errs = append(errs, fmt.Errorf("%s must be in [0, 1], got %v", name, v))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Result is the expected result of a competitor in the generated race.
+type Result struct {
+	CompetitorID int
+	Status       domain.Status
+	TotalTime    time.Duration // Set for finishers, including time penalties of the race format
+	Hits         int
+	Shots        int
+}
+
+// Race is a generated race.
+type Race struct {
+	Events   []domain.Event // Ordered by time
+	Expected []Result       // Ordered by competitor ID
+}
+
+const (
+	// minSpeed bounds the speed distribution, in m/s.
+	minSpeed = 1.0
+	// registrationLead is the time before the start when competitors register and the draw is made.
+	registrationLead = 30 * time.Minute
+	drawLead         = 10 * time.Minute
+	// startLineLead is the time before the scheduled start a competitor enters the start line.
+	startLineLead = 30 * time.Second
+)
+
+// Generate produces a race for the configuration. The same parameters always produce the same race.
+// Every lap has a firing stage halfway through, on the firing lines in turn, unless the config has no firing lines.
+// Competitors missing targets ski one penalty loop per miss in the sprint and mass start formats,
+// the number of loops is reported when they leave the penalty area.
+func Generate(cfg *config.Config, p Params) (Race, error) {
+	if err := p.Validate(); err != nil {
+		return Race{}, err
+	}
+
+	g := &generator{
+		cfg: cfg,
+		p:   p,
+		rnd: rand.New(rand.NewPCG(p.Seed, p.Seed^0x9e3779b97f4a7c15)),
+	}
+	var race Race
+	for id := 1; id <= p.Competitors; id++ {
+		events, result := g.competitor(id)
+		race.Events = append(race.Events, events...)
+		race.Expected = append(race.Expected, result)
+	}
+
+	// Events of a competitor are generated in order, a stable sort keeps the order of equal times
+	slices.SortStableFunc(race.Events, func(a, b domain.Event) int {
+		return a.Time.Compare(b.Time)
+	})
+	return race, nil
+}
+
+type generator struct {
+	cfg *config.Config
+	p   Params
+	rnd *rand.Rand
+}
+
+// at truncates the time to milliseconds as in the events file.
+func at(t time.Time) time.Time {
+	return t.Truncate(time.Millisecond)
+}
+
+// between returns a random duration in [lo, hi).
+func (g *generator) between(lo, hi time.Duration) time.Duration {
+	return lo + time.Duration(g.rnd.Float64()*float64(hi-lo))
+}
+
+// skiing returns the time to ski the distance at the speed with a small variation.
+func (g *generator) skiing(distance int, speed float64) time.Duration {
+	seconds := float64(distance) / speed * (0.97 + 0.06*g.rnd.Float64())
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (g *generator) competitor(id int) ([]domain.Event, Result) {
+	cfg := g.cfg
+	result := Result{CompetitorID: id, Status: domain.StatusNotStarted}
+	var events []domain.Event
+	emit := func(t time.Time, eventID domain.EventID, comments string) time.Time {
+		t = at(t)
+		events = append(events, domain.Event{Time: t, ID: eventID, CompetitorID: id, Comments: comments})
+		return t
+	}
+
+	massStart := cfg.Format == config.RaceMassStart
+	scheduled := cfg.StartTime
+	if !massStart {
+		scheduled = cfg.StartTime.Add(time.Duration(id-1) * cfg.StartDelta)
+	}
+
+	emit(cfg.StartTime.Add(-registrationLead), domain.EventCompetitorRegistered, "")
+	if !massStart {
+		emit(cfg.StartTime.Add(-drawLead), domain.EventStartTimeSet, scheduled.Format("15:04:05.000"))
+	}
+	if g.rnd.Float64() < g.p.DNSRate {
+		return events, result
+	}
+
+	speed := max(minSpeed, g.rnd.NormFloat64()*g.p.SpeedStdDev+g.p.SpeedMean)
+	dnfLap := -1
+	if g.rnd.Float64() < g.p.DNFRate {
+		dnfLap = g.rnd.IntN(cfg.Laps)
+	}
+
+	emit(scheduled.Add(-startLineLead), domain.EventCompetitorOnStartLine, "")
+	reaction := time.Second
+	if !massStart {
+		reaction = min(reaction, cfg.StartDelta/2)
+	}
+	now := emit(scheduled.Add(g.between(0, reaction)), domain.EventCompetitorStarted, "")
+	result.Status = domain.StatusRunning
+
+	misses := 0
+	for lap := 0; lap < cfg.Laps; lap++ {
+		lapTime := g.skiing(cfg.LapLength, speed)
+		if lap == dnfLap {
+			emit(now.Add(lapTime/2), domain.EventCompetitorCanNotContinue, "Lost in the forest")
+			result.Status = domain.StatusNotFinished
+			return events, result
+		}
+
+		// The firing range is in the middle of the lap
+		now = now.Add(lapTime / 2)
+		if cfg.FiringLines > 0 {
+			now = emit(now, domain.EventCompetitorOnFiringRange, strconv.Itoa(lap%cfg.FiringLines+1))
+			stageMisses := 0
+			for target := 1; target <= domain.TargetsPerStage; target++ {
+				now = now.Add(g.between(time.Second, 3*time.Second))
+				if g.rnd.Float64() < g.p.HitProbability {
+					now = emit(now, domain.EventTargetHit, strconv.Itoa(target))
+					result.Hits++
+				} else {
+					stageMisses++
+				}
+			}
+			result.Shots += domain.TargetsPerStage
+			now = emit(now.Add(g.between(time.Second, 2*time.Second)), domain.EventCompetitorLeftFiringRange, "")
+			misses += stageMisses
+
+			if stageMisses > 0 && cfg.Format != config.RaceIndividual {
+				now = emit(now.Add(g.between(3*time.Second, 6*time.Second)), domain.EventCompetitorEnteredPenalty, "")
+				now = emit(now.Add(g.skiing(stageMisses*cfg.PenaltyLength, speed)), domain.EventCompetitorLeftPenalty, strconv.Itoa(stageMisses))
+			}
+		}
+
+		now = emit(now.Add(lapTime-lapTime/2), domain.EventCompetitorEndedMainLap, "")
+	}
+
+	result.Status = domain.StatusFinished
+	result.TotalTime = now.Sub(scheduled)
+	if cfg.Format == config.RaceIndividual {
+		result.TotalTime += time.Duration(misses*cfg.PenaltySeconds) * time.Second
+	}
+	return events, result
+}
+
+// WriteExpected writes the expected results in CSV with the columns
+// competitor_id, status, total_time, hits and shots.
+// The file can be read as previous results by the pursuit command.
+func WriteExpected(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"competitor_id", "status", "total_time", "hits", "shots"}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		totalTime := ""
+		if r.Status == domain.StatusFinished {
+			totalTime = reporting.FormatDuration(r.TotalTime)
+		}
+		row := []string{strconv.Itoa(r.CompetitorID), r.Status.String(), totalTime, strconv.Itoa(r.Hits), strconv.Itoa(r.Shots)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
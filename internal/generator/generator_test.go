@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+	"github.com/Valery223/biathlon-test/internal/pursuit"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+	"github.com/Valery223/biathlon-test/internal/task"
+)
+
+func testConfig(format config.RaceFormat) *config.Config {
+	start, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	return &config.Config{
+		Laps:           3,
+		LapLength:      3500,
+		PenaltyLength:  150,
+		FiringLines:    2,
+		StartTime:      start,
+		StartDelta:     30 * time.Second,
+		Format:         format,
+		PenaltySeconds: config.DefaultPenaltySeconds,
+	}
+}
+
+// run processes the events the way the task does and returns the final reports.
+func run(t testing.TB, cfg *config.Config, events []domain.Event) []reporting.Report {
+	t.Helper()
+	race := task.NewRace(cfg)
+	for i := range events {
+		if _, err := race.Process(&events[i]); err != nil {
+			t.Fatalf("Process %s failed: %v", scannerEvent.FormatLine(events[i]), err)
+		}
+	}
+	race.Close()
+	return race.Reports()
+}
+
+// TestGenerate checks that every generated race is valid and leads to the expected results.
+func TestGenerate(t *testing.T) {
+	params := DefaultParams()
+	params.Competitors = 30
+	params.DNSRate = 0.1
+	params.DNFRate = 0.1
+
+	for _, format := range []config.RaceFormat{config.RaceSprint, config.RaceIndividual, config.RaceMassStart} {
+		for seed := uint64(1); seed <= 5; seed++ {
+			t.Run(fmt.Sprintf("%s/%d", format, seed), func(t *testing.T) {
+				cfg := testConfig(format)
+				params.Seed = seed
+				race, err := Generate(cfg, params)
+				if err != nil {
+					t.Fatalf("Generate failed: %v", err)
+				}
+
+				reports := run(t, cfg, race.Events)
+				if len(reports) != len(race.Expected) {
+					t.Fatalf("Reports: got %d, want %d", len(reports), len(race.Expected))
+				}
+				for i, want := range race.Expected {
+					got := reports[i]
+					if got.CompetitorID != want.CompetitorID || got.Status != want.Status {
+						t.Errorf("Competitor %d: got %d %s, want %s", want.CompetitorID, got.CompetitorID, got.Status, want.Status)
+						continue
+					}
+					if want.Status == domain.StatusFinished && got.TotalTime != want.TotalTime {
+						t.Errorf("Competitor %d: got total time %v, want %v", want.CompetitorID, got.TotalTime, want.TotalTime)
+					}
+					if got.Shots != want.Hits || got.PossibleShots != want.Shots {
+						t.Errorf("Competitor %d: got shots %d/%d, want %d/%d", want.CompetitorID, got.Shots, got.PossibleShots, want.Hits, want.Shots)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	cfg := testConfig(config.RaceSprint)
+	a, err := Generate(cfg, DefaultParams())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	b, _ := Generate(cfg, DefaultParams())
+	if len(a.Events) != len(b.Events) {
+		t.Fatalf("Events: got %d and %d", len(a.Events), len(b.Events))
+	}
+	for i := range a.Events {
+		if a.Events[i] != b.Events[i] {
+			t.Fatalf("Event %d differs: %+v and %+v", i, a.Events[i], b.Events[i])
+		}
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *Params)
+	}{
+		{name: "NoCompetitors", modify: func(p *Params) { p.Competitors = 0 }},
+		{name: "ZeroSpeed", modify: func(p *Params) { p.SpeedMean = 0 }},
+		{name: "NegativeStdDev", modify: func(p *Params) { p.SpeedStdDev = -1 }},
+		{name: "HitProbability", modify: func(p *Params) { p.HitProbability = 1.5 }},
+		{name: "DNSRate", modify: func(p *Params) { p.DNSRate = -0.1 }},
+	}
+
+	if err := DefaultParams().Validate(); err != nil {
+		t.Fatalf("Default params must be valid: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DefaultParams()
+			tt.modify(&p)
+			if err := p.Validate(); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestWriteExpected(t *testing.T) {
+	cfg := testConfig(config.RaceSprint)
+	race, err := Generate(cfg, DefaultParams())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExpected(&buf, race.Expected); err != nil {
+		t.Fatalf("WriteExpected failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "competitor_id,status,total_time,hits,shots\n") {
+		t.Errorf("Unexpected header: %q", strings.SplitN(buf.String(), "\n", 2)[0])
+	}
+
+	// The expected results can seed a pursuit
+	path := t.TempDir() + "/expected.csv"
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results, err := pursuit.LoadResults(path)
+	if err != nil {
+		t.Fatalf("LoadResults failed: %v", err)
+	}
+	if len(results) != len(race.Expected) {
+		t.Errorf("Results: got %d, want %d", len(results), len(race.Expected))
+	}
+}
+
+func BenchmarkProcess(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			cfg := testConfig(config.RaceMassStart)
+			params := DefaultParams()
+			params.Competitors = n
+			race, err := Generate(cfg, params)
+			if err != nil {
+				b.Fatalf("Generate failed: %v", err)
+			}
+			b.ReportMetric(float64(len(race.Events)), "events/op")
+			for b.Loop() {
+				events := make([]domain.Event, len(race.Events))
+				copy(events, race.Events)
+				run(b, cfg, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package pursuit
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// Start is the handicapped start of a competitor in the pursuit.
+type Start struct {
+	CompetitorID int
+	Behind       time.Duration // Time behind the winner of the previous race, capped
+	Time         time.Time
+}
+
+// Draw computes the start times of the pursuit: the leader starts at leaderStart,
+// every other finisher starts later by the time behind the leader of the previous race.
+// If maxBehind is positive, competitors further behind all start at leaderStart+maxBehind.
+// Competitors who did not finish the previous race do not qualify for the pursuit.
+// Starts are ordered by start time, then by competitor ID.
+func Draw(results []Result, leaderStart time.Time, maxBehind time.Duration) []Start {
+	finishers := slices.DeleteFunc(slices.Clone(results), func(r Result) bool {
+		return !r.Finished
+	})
+	if len(finishers) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(finishers, func(a, b Result) int {
+		if a.TotalTime != b.TotalTime {
+			if a.TotalTime < b.TotalTime {
+				return -1
+			}
+			return 1
+		}
+		return a.CompetitorID - b.CompetitorID
+	})
+
+	leader := finishers[0].TotalTime
+	starts := make([]Start, 0, len(finishers))
+	for _, r := range finishers {
+		behind := r.TotalTime - leader
+		if maxBehind > 0 {
+			behind = min(behind, maxBehind)
+		}
+		starts = append(starts, Start{
+			CompetitorID: r.CompetitorID,
+			Behind:       behind,
+			Time:         leaderStart.Add(behind),
+		})
+	}
+	return starts
+}
+
+// DrawEvent returns the EventStartTimeSet event assigning the start, issued at the given time.
+func (s Start) DrawEvent(at time.Time) domain.Event {
+	return domain.Event{
+		Time:         at,
+		ID:           domain.EventStartTimeSet,
+		CompetitorID: s.CompetitorID,
+		Comments:     s.Time.Format("15:04:05.000"),
+	}
+}
+
+// ScannerEvent is the source of events, see task.ScannerEvent.
+type ScannerEvent interface {
+	Scan(*domain.Event) error
+}
+
+// DrawScanner reads the events of a pursuit race and assigns the handicapped start times:
+// the draw event of a competitor is inserted right after their registration,
+// draw events present in the source are dropped.
+// A registered competitor without a start, i.e. without a finish in the previous race, is an error.
+type DrawScanner struct {
+	source  ScannerEvent
+	starts  map[int]Start
+	pending *domain.Event
+}
+
+// NewDrawScanner creates a DrawScanner reading events from source.
+func NewDrawScanner(source ScannerEvent, starts []Start) *DrawScanner {
+	s := &DrawScanner{
+		source: source,
+		starts: make(map[int]Start, len(starts)),
+	}
+	for _, start := range starts {
+		s.starts[start.CompetitorID] = start
+	}
+	return s
+}
+
+// Line returns the input line of the last scanned event if the source tracks lines, otherwise 0.
+// An inserted draw event has the line of the registration it follows.
+func (s *DrawScanner) Line() int {
+	if source, ok := s.source.(interface{ Line() int }); ok {
+		return source.Line()
+	}
+	return 0
+}
+
+// Scan implements task.ScannerEvent.
+func (s *DrawScanner) Scan(e *domain.Event) error {
+	if s.pending != nil {
+		*e = *s.pending
+		s.pending = nil
+		return nil
+	}
+
+	for {
+		if err := s.source.Scan(e); err != nil {
+			return err
+		}
+		if e.ID != domain.EventStartTimeSet {
+			break
+		}
+	}
+
+	if e.ID == domain.EventCompetitorRegistered {
+		start, ok := s.starts[e.CompetitorID]
+		if !ok {
+			return fmt.Errorf("competitor %d has no finish result in the previous race", e.CompetitorID)
+		}
+		draw := start.DrawEvent(e.Time)
+		s.pending = &draw
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package pursuit
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+)
+
+func TestLoadResults(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "results.json",
+			content: `[{"rank":1,"competitorId":2,"status":"Finished","totalTime":"00:25:18.356","laps":[]},
+				{"rank":2,"competitorId":1,"status":"Finished","totalTime":"00:25:26.047","laps":[]},
+				{"competitorId":3,"status":"NotFinished","laps":[]}]`,
+		},
+		{
+			name: "results.csv",
+			content: "rank,competitor_id,status,total_time,gap_to_leader\n" +
+				"1,2,Finished,00:25:18.356,00:00:00.000\n" +
+				"2,1,Finished,00:25:26.047,00:00:07.691\n" +
+				"-,3,NotFinished,,\n",
+		},
+	}
+
+	want := []Result{
+		{CompetitorID: 2, Finished: true, TotalTime: 25*time.Minute + 18356*time.Millisecond},
+		{CompetitorID: 1, Finished: true, TotalTime: 25*time.Minute + 26047*time.Millisecond},
+		{CompetitorID: 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.name)
+			if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+				t.Fatalf("failed to write results: %v", err)
+			}
+
+			got, err := LoadResults(path)
+			if err != nil {
+				t.Fatalf("LoadResults failed: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d results, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("result %d: got %+v, want %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDraw(t *testing.T) {
+	leaderStart, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	results := []Result{
+		{CompetitorID: 1, Finished: true, TotalTime: 25*time.Minute + 30*time.Second},
+		{CompetitorID: 2, Finished: true, TotalTime: 25 * time.Minute},
+		{CompetitorID: 3},
+		{CompetitorID: 4, Finished: true, TotalTime: 29 * time.Minute},
+	}
+
+	got := Draw(results, leaderStart, 2*time.Minute)
+	want := []struct {
+		id     int
+		behind time.Duration
+	}{
+		{2, 0},
+		{1, 30 * time.Second},
+		// Capped, 4 minutes behind
+		{4, 2 * time.Minute},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d starts, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].CompetitorID != w.id || got[i].Behind != w.behind || !got[i].Time.Equal(leaderStart.Add(w.behind)) {
+			t.Errorf("start %d: got %+v, want competitor %d behind %v", i, got[i], w.id, w.behind)
+		}
+	}
+}
+
+func TestDrawScanner(t *testing.T) {
+	input := strings.Join([]string{
+		"[09:00:00.000] 1 1",
+		"[09:00:01.000] 1 2",
+		"[09:30:00.000] 2 1 10:00:00.000",
+		"[10:00:00.500] 4 2",
+	}, "\n")
+	leaderStart, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	starts := []Start{
+		{CompetitorID: 2, Time: leaderStart},
+		{CompetitorID: 1, Behind: 30 * time.Second, Time: leaderStart.Add(30 * time.Second)},
+	}
+
+	sc := NewDrawScanner(scannerEvent.NewScanner(strings.NewReader(input)), starts)
+	var got []string
+	for {
+		var e domain.Event
+		err := sc.Scan(&e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		got = append(got, scannerEvent.FormatLine(e))
+	}
+
+	want := []string{
+		"[09:00:00.000] 1 1",
+		"[09:00:00.000] 2 1 10:00:30.000",
+		"[09:00:01.000] 1 2",
+		"[09:00:01.000] 2 2 10:00:00.000",
+		"[10:00:00.500] 4 2",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events mismatch:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	t.Run("NoResult", func(t *testing.T) {
+		// Competitor 3 did not finish the previous race, the source draw must not be used instead
+		input := "[09:00:00.000] 1 3\n[09:30:00.000] 2 3 10:00:00.000"
+		sc := NewDrawScanner(scannerEvent.NewScanner(strings.NewReader(input)), starts)
+		var e domain.Event
+		err := sc.Scan(&e)
+		if err == nil || !strings.Contains(err.Error(), "competitor 3 has no finish result") {
+			t.Errorf("Scan: got %v, want error for competitor 3", err)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+// Package pursuit generates the handicapped start times of a pursuit race from the results of a previous race.
+package pursuit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+	"github.com/Valery223/biathlon-test/internal/racetime"
+)
+
+// Result is the result of a competitor in the previous race.
+type Result struct {
+	CompetitorID int
+	Finished     bool
+	TotalTime    time.Duration // Valid only for finishers
+}
+
+// LoadResults reads the results of a previous race from the JSON report output (.json)
+// or the results CSV (.csv) written with the -format flag.
+func LoadResults(path string) ([]Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+
+	var results []Result
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		results, err = parseJSONResults(data)
+	case ".csv":
+		results, err = parseCSVResults(data)
+	default:
+		return nil, fmt.Errorf("unsupported results file extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
+
+// jsonResult holds the fields of the JSON report output used for the pursuit.
+type jsonResult struct {
+	CompetitorID int    `json:"competitorId"`
+	Status       string `json:"status"`
+	TotalTime    string `json:"totalTime"`
+}
+
+func parseJSONResults(data []byte) ([]Result, error) {
+	var rows []jsonResult
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+
+	results := make([]Result, 0, len(rows))
+	for _, row := range rows {
+		r, err := newResult(row.CompetitorID, row.Status, row.TotalTime)
+		if err != nil {
+			return nil, fmt.Errorf("competitor %d: %w", row.CompetitorID, err)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func parseCSVResults(data []byte) ([]Result, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("missing header")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[name] = i
+	}
+	for _, name := range []string{"competitor_id", "status", "total_time"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	results := make([]Result, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		id, err := strconv.Atoi(row[columns["competitor_id"]])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid competitor id %q", i+2, row[columns["competitor_id"]])
+		}
+		r, err := newResult(id, row[columns["status"]], row[columns["total_time"]])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+2, err)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func newResult(id int, status, totalTime string) (Result, error) {
+	r := Result{CompetitorID: id}
+	if status != domain.StatusFinished.String() {
+		return r, nil
+	}
+
+	d, err := racetime.ParseDuration(totalTime)
+	if err != nil {
+		return r, fmt.Errorf("invalid total time: %w", err)
+	}
+	r.Finished = true
+	r.TotalTime = d
+	return r, nil
+}
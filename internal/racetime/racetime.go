@@ -0,0 +1,91 @@
+// Package racetime parses and formats race times and durations used in the configuration.
+package racetime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// midnight is the date used by time.Parse for layouts without a date.
+// Times of day are placed on this date to be comparable with event times.
+var midnight = time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ParseDuration parses a duration in one of the formats:
+// "HH:MM:SS", "HH:MM:SS.sss" or a Go duration string such as "30s" or "1m30.5s".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, ":") {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return d, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid duration %q: expected HH:MM:SS[.sss]", s)
+	}
+	secPart, fracPart, hasFrac := strings.Cut(parts[2], ".")
+
+	hours, errH := strconv.Atoi(parts[0])
+	minutes, errM := strconv.Atoi(parts[1])
+	seconds, errS := strconv.Atoi(secPart)
+	if errH != nil || errM != nil || errS != nil ||
+		hours < 0 || minutes < 0 || minutes > 59 || seconds < 0 || seconds > 59 {
+		return 0, fmt.Errorf("invalid duration %q: expected HH:MM:SS[.sss]", s)
+	}
+
+	d := time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second
+
+	if hasFrac {
+		if fracPart == "" || len(fracPart) > 9 {
+			return 0, fmt.Errorf("invalid duration %q: invalid fraction of a second", s)
+		}
+		ns, err := strconv.Atoi(fracPart + strings.Repeat("0", 9-len(fracPart)))
+		if err != nil || ns < 0 {
+			return 0, fmt.Errorf("invalid duration %q: invalid fraction of a second", s)
+		}
+		d += time.Duration(ns)
+	}
+
+	return d, nil
+}
+
+// ParseTimeOfDay parses a time of day in any format accepted by ParseDuration.
+// The result has the same date as times parsed by time.Parse("15:04:05.000", ...).
+func ParseTimeOfDay(s string) (time.Time, error) {
+	d, err := ParseDuration(s)
+	if err != nil || d < 0 || d >= 24*time.Hour {
+		return time.Time{}, fmt.Errorf("invalid time of day %q", s)
+	}
+	return midnight.Add(d), nil
+}
+
+// FormatDuration formats a duration as "HH:MM:SS".
+// Milliseconds are appended as "HH:MM:SS.sss" only if the duration has a fraction of a second.
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	h := int(d / time.Hour)
+	m := int(d/time.Minute) % 60
+	s := int(d/time.Second) % 60
+	ms := int(d/time.Millisecond) % 1000
+	if ms == 0 {
+		return fmt.Sprintf("%s%02d:%02d:%02d", sign, h, m, s)
+	}
+	return fmt.Sprintf("%s%02d:%02d:%02d.%03d", sign, h, m, s, ms)
+}
+
+// FormatTimeOfDay formats a time of day in the same way as FormatDuration.
+func FormatTimeOfDay(t time.Time) string {
+	y, mo, d := t.Date()
+	return FormatDuration(t.Sub(time.Date(y, mo, d, 0, 0, 0, 0, t.Location())))
+}
@@ -0,0 +1,69 @@
+package racetime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "00:00:30", want: 30 * time.Second},
+		{in: "00:01:30.500", want: 90*time.Second + 500*time.Millisecond},
+		{in: "01:00:00.5", want: time.Hour + 500*time.Millisecond},
+		{in: "30s", want: 30 * time.Second},
+		{in: "1m30.5s", want: 90*time.Second + 500*time.Millisecond},
+		{in: "00:60:00", wantErr: true},
+		{in: "00:00", wantErr: true},
+		{in: "00:00:30.", wantErr: true},
+		{in: "thirty", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseDuration(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("ParseDuration(%q): expected error, got %v", tc.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDuration(%q) failed: %v", tc.in, err)
+			}
+			if got != tc.want {
+				t.Errorf("ParseDuration(%q): got %v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	want, _ := time.Parse("15:04:05.000", "09:30:00.000")
+	for _, in := range []string{"09:30:00", "09:30:00.000", "9h30m"} {
+		got, err := ParseTimeOfDay(in)
+		if err != nil {
+			t.Fatalf("ParseTimeOfDay(%q) failed: %v", in, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseTimeOfDay(%q): got %v, want %v", in, got, want)
+		}
+	}
+
+	if _, err := ParseTimeOfDay("24:00:00"); err == nil {
+		t.Errorf("ParseTimeOfDay(%q): expected error", "24:00:00")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	if got := FormatDuration(30 * time.Second); got != "00:00:30" {
+		t.Errorf("FormatDuration: got %q, want %q", got, "00:00:30")
+	}
+	if got := FormatDuration(90*time.Second + 500*time.Millisecond); got != "00:01:30.500" {
+		t.Errorf("FormatDuration: got %q, want %q", got, "00:01:30.500")
+	}
+}
@@ -0,0 +1,142 @@
+// Package registry loads the start list which maps competitor IDs to athlete data.
+package registry
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// Registry is the start list of a race.
+type Registry struct {
+	athletes map[int]domain.Athlete
+}
+
+// New creates a Registry from athletes keyed by competitor ID.
+func New(athletes map[int]domain.Athlete) *Registry {
+	return &Registry{athletes: athletes}
+}
+
+// Athlete returns the athlete data of the competitor and whether the competitor is in the start list.
+func (r *Registry) Athlete(id int) (domain.Athlete, bool) {
+	a, ok := r.athletes[id]
+	return a, ok
+}
+
+// Len returns the number of competitors in the start list.
+func (r *Registry) Len() int {
+	return len(r.athletes)
+}
+
+// Load reads the start list from a JSON (.json) or CSV (.csv) file.
+//
+// The JSON file is an array of objects with the fields id, bib, name, nation, club, gender and category.
+// The CSV file has a header row with the same column names, only the id column is required.
+func Load(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read start list: %w", err)
+	}
+
+	var entries []entry
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		err = json.Unmarshal(data, &entries)
+	case ".csv":
+		entries, err = parseCSV(data)
+	default:
+		return nil, fmt.Errorf("unsupported start list file extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to parse start list: %w", path, err)
+	}
+
+	athletes := make(map[int]domain.Athlete, len(entries))
+	for i, e := range entries {
+		if e.ID <= 0 {
+			return nil, fmt.Errorf("%s: entry %d: invalid competitor id %d", path, i+1, e.ID)
+		}
+		if _, ok := athletes[e.ID]; ok {
+			return nil, fmt.Errorf("%s: entry %d: duplicate competitor id %d", path, i+1, e.ID)
+		}
+		athletes[e.ID] = e.athlete()
+	}
+	return New(athletes), nil
+}
+
+// entry is a single row of the start list file.
+type entry struct {
+	ID       int    `json:"id"`
+	Bib      int    `json:"bib"`
+	Name     string `json:"name"`
+	Nation   string `json:"nation"`
+	Club     string `json:"club"`
+	Gender   string `json:"gender"`
+	Category string `json:"category"`
+}
+
+func (e entry) athlete() domain.Athlete {
+	return domain.Athlete{
+		Bib:      e.Bib,
+		Name:     e.Name,
+		Nation:   e.Nation,
+		Club:     e.Club,
+		Gender:   e.Gender,
+		Category: e.Category,
+	}
+}
+
+func parseCSV(data []byte) ([]entry, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("missing header")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		return nil, fmt.Errorf("missing column %q", "id")
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	entries := make([]entry, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		id, err := strconv.Atoi(field(row, "id"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid id %q", i+2, field(row, "id"))
+		}
+		e := entry{
+			ID:       id,
+			Name:     field(row, "name"),
+			Nation:   field(row, "nation"),
+			Club:     field(row, "club"),
+			Gender:   field(row, "gender"),
+			Category: field(row, "category"),
+		}
+		if bib := field(row, "bib"); bib != "" {
+			e.Bib, err = strconv.Atoi(bib)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid bib %q", i+2, bib)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
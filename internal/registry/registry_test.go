@@ -0,0 +1,77 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func writeStartList(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write start list: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "start_list.csv",
+			content: "id,bib,name,nation,club,gender,category\n1,11,Anna Berg,SWE,Ostersund,F,Senior\n2,,Ole Dahl,NOR,,M,Junior\n",
+		},
+		{
+			name: "start_list.json",
+			content: `[{"id": 1, "bib": 11, "name": "Anna Berg", "nation": "SWE", "club": "Ostersund", "gender": "F", "category": "Senior"},
+				{"id": 2, "name": "Ole Dahl", "nation": "NOR", "gender": "M", "category": "Junior"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reg, err := Load(writeStartList(t, tc.name, tc.content))
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if reg.Len() != 2 {
+				t.Errorf("Len: got %d, want 2", reg.Len())
+			}
+
+			got, ok := reg.Athlete(1)
+			want := domain.Athlete{Bib: 11, Name: "Anna Berg", Nation: "SWE", Club: "Ostersund", Gender: "F", Category: "Senior"}
+			if !ok || got != want {
+				t.Errorf("Athlete(1): got %+v, %v, want %+v", got, ok, want)
+			}
+			if got, _ := reg.Athlete(2); got.String() != "Ole Dahl (NOR)" {
+				t.Errorf("Athlete(2).String: got %q", got.String())
+			}
+			if _, ok := reg.Athlete(3); ok {
+				t.Errorf("Athlete(3): expected unknown competitor")
+			}
+		})
+	}
+
+	errorCases := []struct {
+		name, content, want string
+	}{
+		{"duplicate.csv", "id,name\n1,Anna\n1,Ole\n", "duplicate competitor id 1"},
+		{"no_id.csv", "bib,name\n11,Anna\n", `missing column "id"`},
+		{"bad_bib.csv", "id,bib\n1,eleven\n", `invalid bib "eleven"`},
+		{"start_list.txt", "", "unsupported start list file extension"},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writeStartList(t, tc.name, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Load: got error %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
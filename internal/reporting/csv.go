@@ -0,0 +1,148 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// WriteResultsCSV writes one row per competitor with rank, status, total time and lap columns.
+// The number of lap columns is given by laps, missing laps are left empty.
+// Athlete columns follow competitor_id if the race is run with a start list.
+func WriteResultsCSV(w io.Writer, standings []Standing, laps int) error {
+	cw := csv.NewWriter(w)
+	athletes := hasAthletes(standings)
+
+	header := []string{"rank", "competitor_id"}
+	if athletes {
+		header = append(header, athleteHeader...)
+	}
+	header = append(header, "status", "total_time", "gap_to_leader")
+	for i := 1; i <= laps; i++ {
+		header = append(header, fmt.Sprintf("lap%d_time", i), fmt.Sprintf("lap%d_speed", i))
+	}
+	header = append(header, "penalty_time", "penalty_speed", "hits", "shots")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range standings {
+		r := s.Report
+		row := []string{formatRank(s.Rank), strconv.Itoa(r.CompetitorID)}
+		if athletes {
+			row = append(row, athleteColumns(r.Athlete)...)
+		}
+		row = append(row, r.Status.String(), "", "")
+		if r.Status == domain.StatusFinished {
+			row[len(row)-2] = FormatDuration(r.TotalTime)
+		}
+		if s.Rank > 0 {
+			row[len(row)-1] = FormatDuration(s.GapToLeader)
+		}
+		for i := 0; i < laps; i++ {
+			if i < len(r.LapsStatistics) && r.LapsStatistics[i].Duration > 0 {
+				lap := r.LapsStatistics[i]
+				row = append(row, FormatDuration(lap.Duration), formatSpeed(lap.AverageSpeed))
+			} else {
+				row = append(row, "", "")
+			}
+		}
+		if r.NoPenaltyLaps {
+			row = append(row, "", "")
+		} else {
+			row = append(row, FormatDuration(r.PenaltyLapStatictic.Duration), formatSpeed(r.PenaltyLapStatictic.AverageSpeed))
+		}
+		row = append(row,
+			strconv.Itoa(r.Shots),
+			strconv.Itoa(r.PossibleShots),
+		)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSplitsCSV writes one row per completed lap of each competitor (long format).
+// Elapsed is the time since the scheduled start at the end of the lap.
+// Athlete columns follow competitor_id if the race is run with a start list.
+func WriteSplitsCSV(w io.Writer, standings []Standing) error {
+	cw := csv.NewWriter(w)
+	athletes := hasAthletes(standings)
+
+	header := []string{"competitor_id"}
+	if athletes {
+		header = append(header, athleteHeader...)
+	}
+	header = append(header, "rank", "status", "lap", "lap_time", "lap_speed", "elapsed")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range standings {
+		r := s.Report
+		var elapsed time.Duration
+		for i, lap := range r.LapsStatistics {
+			if lap.Duration <= 0 {
+				break
+			}
+			elapsed += lap.Duration
+			row := []string{strconv.Itoa(r.CompetitorID)}
+			if athletes {
+				row = append(row, athleteColumns(r.Athlete)...)
+			}
+			row = append(row,
+				formatRank(s.Rank),
+				r.Status.String(),
+				strconv.Itoa(i+1),
+				FormatDuration(lap.Duration),
+				formatSpeed(lap.AverageSpeed),
+				FormatDuration(elapsed),
+			)
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// athleteHeader names the columns written by athleteColumns.
+var athleteHeader = []string{"bib", "name", "nation", "club", "gender", "category"}
+
+// hasAthletes reports whether some competitor has start list data.
+func hasAthletes(standings []Standing) bool {
+	for _, s := range standings {
+		if !s.Report.Athlete.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+func athleteColumns(a domain.Athlete) []string {
+	bib := ""
+	if a.Bib > 0 {
+		bib = strconv.Itoa(a.Bib)
+	}
+	return []string{bib, a.Name, a.Nation, a.Club, a.Gender, a.Category}
+}
+
+func formatRank(rank int) string {
+	if rank == 0 {
+		return ""
+	}
+	return strconv.Itoa(rank)
+}
+
+func formatSpeed(speed float64) string {
+	return strconv.FormatFloat(speed, 'f', 3, 64)
+}
@@ -0,0 +1,95 @@
+package reporting
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestWriteCSV(t *testing.T) {
+	standings := []Standing{
+		{
+			Rank: 1,
+			Report: Report{
+				CompetitorID: 2,
+				Status:       domain.StatusFinished,
+				TotalTime:    21 * time.Minute,
+				LapsStatistics: []LapStat{
+					{Duration: 10 * time.Minute, AverageSpeed: 5},
+					{Duration: 11 * time.Minute, AverageSpeed: 4.545},
+				},
+				PenaltyLapStatictic: LapStat{Duration: time.Minute, AverageSpeed: 1.5},
+				Shots:               9,
+				PossibleShots:       10,
+			},
+		},
+		{
+			Report: Report{
+				CompetitorID:   1,
+				Status:         domain.StatusNotFinished,
+				LapsStatistics: []LapStat{{Duration: 12 * time.Minute, AverageSpeed: 4}, {}},
+				Shots:          4,
+				PossibleShots:  5,
+			},
+		},
+	}
+
+	t.Run("results", func(t *testing.T) {
+		var b strings.Builder
+		if err := WriteResultsCSV(&b, standings, 2); err != nil {
+			t.Fatalf("WriteResultsCSV failed: %v", err)
+		}
+		want := "rank,competitor_id,status,total_time,gap_to_leader,lap1_time,lap1_speed,lap2_time,lap2_speed,penalty_time,penalty_speed,hits,shots\n" +
+			"1,2,Finished,00:21:00.000,00:00:00.000,00:10:00.000,5.000,00:11:00.000,4.545,00:01:00.000,1.500,9,10\n" +
+			",1,NotFinished,,,00:12:00.000,4.000,,,00:00:00.000,0.000,4,5\n"
+		if b.String() != want {
+			t.Errorf("WriteResultsCSV:\ngot:\n%s\nwant:\n%s", b.String(), want)
+		}
+	})
+
+	t.Run("splits", func(t *testing.T) {
+		var b strings.Builder
+		if err := WriteSplitsCSV(&b, standings); err != nil {
+			t.Fatalf("WriteSplitsCSV failed: %v", err)
+		}
+		want := "competitor_id,rank,status,lap,lap_time,lap_speed,elapsed\n" +
+			"2,1,Finished,1,00:10:00.000,5.000,00:10:00.000\n" +
+			"2,1,Finished,2,00:11:00.000,4.545,00:21:00.000\n" +
+			"1,,NotFinished,1,00:12:00.000,4.000,00:12:00.000\n"
+		if b.String() != want {
+			t.Errorf("WriteSplitsCSV:\ngot:\n%s\nwant:\n%s", b.String(), want)
+		}
+	})
+
+	t.Run("start list", func(t *testing.T) {
+		// Competitor 1 is missing from the start list, its athlete columns are left empty
+		withAthletes := slices.Clone(standings)
+		withAthletes[0].Report.Athlete = domain.Athlete{Bib: 12, Name: "Ole Dahl", Nation: "NOR", Club: "Lillehammer SK", Gender: "M", Category: "Senior"}
+
+		var results strings.Builder
+		if err := WriteResultsCSV(&results, withAthletes, 1); err != nil {
+			t.Fatalf("WriteResultsCSV failed: %v", err)
+		}
+		want := "rank,competitor_id,bib,name,nation,club,gender,category,status,total_time,gap_to_leader,lap1_time,lap1_speed,penalty_time,penalty_speed,hits,shots\n" +
+			"1,2,12,Ole Dahl,NOR,Lillehammer SK,M,Senior,Finished,00:21:00.000,00:00:00.000,00:10:00.000,5.000,00:01:00.000,1.500,9,10\n" +
+			",1,,,,,,,NotFinished,,,00:12:00.000,4.000,00:00:00.000,0.000,4,5\n"
+		if results.String() != want {
+			t.Errorf("WriteResultsCSV:\ngot:\n%s\nwant:\n%s", results.String(), want)
+		}
+
+		var splits strings.Builder
+		if err := WriteSplitsCSV(&splits, withAthletes); err != nil {
+			t.Fatalf("WriteSplitsCSV failed: %v", err)
+		}
+		want = "competitor_id,bib,name,nation,club,gender,category,rank,status,lap,lap_time,lap_speed,elapsed\n" +
+			"2,12,Ole Dahl,NOR,Lillehammer SK,M,Senior,1,Finished,1,00:10:00.000,5.000,00:10:00.000\n" +
+			"2,12,Ole Dahl,NOR,Lillehammer SK,M,Senior,1,Finished,2,00:11:00.000,4.545,00:21:00.000\n" +
+			"1,,,,,,,,NotFinished,1,00:12:00.000,4.000,00:12:00.000\n"
+		if splits.String() != want {
+			t.Errorf("WriteSplitsCSV:\ngot:\n%s\nwant:\n%s", splits.String(), want)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+)
+
+// Format is the output format of the final report.
+type Format string
+
+// Supported output formats.
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+// ParseFormat converts a string into a Format.
+// It returns an error if the format is not supported.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatText, FormatJSON, FormatCSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q", s)
+	}
+}
+
+// WriteStandings writes the standings to w in the given format.
+// The text format writes one line per competitor, the JSON format writes a single array,
+// the CSV format writes the results table with lap columns sized to cfg.Laps.
+func WriteStandings(w io.Writer, standings []Standing, format Format, cfg *config.Config) error {
+	switch format {
+	case FormatText:
+		for _, s := range standings {
+			if _, err := fmt.Fprintln(w, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(standings)
+	case FormatCSV:
+		return WriteResultsCSV(w, standings, cfg.Laps)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
@@ -0,0 +1,241 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// Field is an attribute of the athlete used to group results.
+type Field string
+
+// Supported fields.
+const (
+	FieldCategory Field = "category"
+	FieldGender   Field = "gender"
+	FieldNation   Field = "nation"
+	FieldClub     Field = "club"
+)
+
+// ParseField converts a string into a Field.
+func ParseField(s string) (Field, error) {
+	switch f := Field(s); f {
+	case FieldCategory, FieldGender, FieldNation, FieldClub:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown field %q", s)
+	}
+}
+
+// ParseFields converts a comma separated list into fields, e.g. "category,gender".
+func ParseFields(s string) ([]Field, error) {
+	var fields []Field
+	for _, part := range strings.Split(s, ",") {
+		f, err := ParseField(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// Of returns the value of the field for the athlete.
+func (f Field) Of(a domain.Athlete) string {
+	switch f {
+	case FieldCategory:
+		return a.Category
+	case FieldGender:
+		return a.Gender
+	case FieldNation:
+		return a.Nation
+	case FieldClub:
+		return a.Club
+	default:
+		return ""
+	}
+}
+
+// Group is the standings of the competitors sharing the values of the grouping fields.
+type Group struct {
+	// Name joins the values of the fields with " / ", a missing value is shown as "-".
+	Name      string
+	Standings []Standing
+}
+
+// GroupStandings splits the reports by the values of the fields and ranks every group separately.
+// Groups are ordered by name. Without fields all reports form a single group with an empty name.
+func GroupStandings(reports []Report, fields ...Field) []Group {
+	byName := make(map[string][]Report)
+	for _, r := range reports {
+		byName[groupName(r.Athlete, fields)] = append(byName[groupName(r.Athlete, fields)], r)
+	}
+
+	groups := make([]Group, 0, len(byName))
+	for name, members := range byName {
+		groups = append(groups, Group{Name: name, Standings: BuildStandings(members)})
+	}
+	slices.SortFunc(groups, func(a, b Group) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return groups
+}
+
+func groupName(a domain.Athlete, fields []Field) string {
+	values := make([]string, 0, len(fields))
+	for _, f := range fields {
+		v := f.Of(a)
+		if v == "" {
+			v = "-"
+		}
+		values = append(values, v)
+	}
+	return strings.Join(values, " / ")
+}
+
+// TeamStanding is a single row of the nation or team aggregate table.
+type TeamStanding struct {
+	// Rank is 0 for teams with fewer finishers than the number of counted times.
+	Rank int
+	Team string
+	// Counted is the IDs of the finishers whose times are summed, fastest first.
+	Counted []int
+	Time    time.Duration
+	Gold    int
+	Silver  int
+	Bronze  int
+}
+
+// BuildTeamStandings aggregates the groups by the team field (usually FieldNation or FieldClub).
+// The time of a team is the sum of the best bestN times of its finishers, all finishers count if bestN <= 0.
+// Medals are counted from the ranks within the groups, so every group awards its own medals.
+// Teams with enough finishers are ranked by ascending time, the others follow unranked.
+// Competitors without a team are ignored.
+func BuildTeamStandings(groups []Group, by Field, bestN int) []TeamStanding {
+	teams := make(map[string]*TeamStanding)
+	times := make(map[string][]Report)
+	for _, g := range groups {
+		for _, s := range g.Standings {
+			name := by.Of(s.Report.Athlete)
+			if name == "" {
+				continue
+			}
+			team, ok := teams[name]
+			if !ok {
+				team = &TeamStanding{Team: name}
+				teams[name] = team
+			}
+			switch s.Rank {
+			case 1:
+				team.Gold++
+			case 2:
+				team.Silver++
+			case 3:
+				team.Bronze++
+			}
+			if s.Report.Status == domain.StatusFinished {
+				times[name] = append(times[name], s.Report)
+			}
+		}
+	}
+
+	standings := make([]TeamStanding, 0, len(teams))
+	for name, team := range teams {
+		finishers := times[name]
+		slices.SortFunc(finishers, func(a, b Report) int {
+			if a.TotalTime != b.TotalTime {
+				if a.TotalTime < b.TotalTime {
+					return -1
+				}
+				return 1
+			}
+			return a.CompetitorID - b.CompetitorID
+		})
+		if bestN > 0 && len(finishers) > bestN {
+			finishers = finishers[:bestN]
+		}
+		for _, r := range finishers {
+			team.Counted = append(team.Counted, r.CompetitorID)
+			team.Time += r.TotalTime
+		}
+		standings = append(standings, *team)
+	}
+
+	complete := func(t TeamStanding) bool {
+		return len(t.Counted) > 0 && (bestN <= 0 || len(t.Counted) == bestN)
+	}
+	slices.SortFunc(standings, func(a, b TeamStanding) int {
+		if complete(a) != complete(b) {
+			if complete(a) {
+				return -1
+			}
+			return 1
+		}
+		if complete(a) && a.Time != b.Time {
+			if a.Time < b.Time {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Team, b.Team)
+	})
+
+	for i := range standings {
+		if !complete(standings[i]) {
+			break
+		}
+		if i > 0 && standings[i].Time == standings[i-1].Time {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+	return standings
+}
+
+// String provides a string representation of the TeamStanding:
+// rank team total_time counted_finishers gold/silver/bronze
+// Unranked teams are marked with "-".
+func (t TeamStanding) String() string {
+	rank := "-"
+	if t.Rank > 0 {
+		rank = fmt.Sprint(t.Rank)
+	}
+	return fmt.Sprintf("%s %s %s %d %d/%d/%d", rank, t.Team, FormatDuration(t.Time), len(t.Counted), t.Gold, t.Silver, t.Bronze)
+}
+
+// WriteGroups writes the standings of every group, each preceded by a "Group name" header.
+func WriteGroups(w io.Writer, groups []Group) error {
+	for _, g := range groups {
+		if _, err := fmt.Fprintf(w, "Group %s\n", g.Name); err != nil {
+			return err
+		}
+		for _, s := range g.Standings {
+			if _, err := fmt.Fprintln(w, s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteTeamStandings writes the aggregate table preceded by a "Teams by field (best N)" header.
+func WriteTeamStandings(w io.Writer, standings []TeamStanding, by Field, bestN int) error {
+	header := fmt.Sprintf("Teams by %s", by)
+	if bestN > 0 {
+		header += fmt.Sprintf(" (best %d)", bestN)
+	}
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		return err
+	}
+	for _, t := range standings {
+		if _, err := fmt.Fprintln(w, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
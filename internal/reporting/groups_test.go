@@ -0,0 +1,89 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestGroupStandings(t *testing.T) {
+	athlete := func(nation, gender, category string) domain.Athlete {
+		return domain.Athlete{Nation: nation, Gender: gender, Category: category}
+	}
+	finished := func(id int, total time.Duration, a domain.Athlete) Report {
+		return Report{CompetitorID: id, Athlete: a, Status: domain.StatusFinished, TotalTime: total}
+	}
+	reports := []Report{
+		finished(1, 25*time.Minute, athlete("NOR", "M", "Senior")),
+		finished(2, 24*time.Minute, athlete("SWE", "M", "Senior")),
+		finished(3, 26*time.Minute, athlete("NOR", "F", "Senior")),
+		finished(4, 27*time.Minute, athlete("NOR", "M", "Junior")),
+		finished(5, 28*time.Minute, athlete("SWE", "F", "")),
+		{CompetitorID: 6, Athlete: athlete("SWE", "M", "Senior"), Status: domain.StatusNotFinished},
+	}
+
+	groups := GroupStandings(reports, FieldCategory, FieldGender)
+	wantGroups := []struct {
+		name string
+		ids  []int
+	}{
+		{"- / F", []int{5}},
+		{"Junior / M", []int{4}},
+		{"Senior / F", []int{3}},
+		{"Senior / M", []int{2, 1, 6}},
+	}
+	if len(groups) != len(wantGroups) {
+		t.Fatalf("got %d groups, want %d: %+v", len(groups), len(wantGroups), groups)
+	}
+	for i, w := range wantGroups {
+		if groups[i].Name != w.name || len(groups[i].Standings) != len(w.ids) {
+			t.Fatalf("group %d: got %q with %d standings, want %q with %d", i, groups[i].Name, len(groups[i].Standings), w.name, len(w.ids))
+		}
+		for j, id := range w.ids {
+			if got := groups[i].Standings[j].Report.CompetitorID; got != id {
+				t.Errorf("group %q, standing %d: got competitor %d, want %d", w.name, j, got, id)
+			}
+		}
+	}
+	if r := groups[3].Standings[1]; r.Rank != 2 || r.GapToLeader != time.Minute {
+		t.Errorf("Ranks within the group: got %+v", r)
+	}
+
+	teams := BuildTeamStandings(groups, FieldNation, 2)
+	wantTeams := []TeamStanding{
+		// NOR counts 1 and 3, SWE counts 2 and 5
+		{Rank: 1, Team: "NOR", Counted: []int{1, 3}, Time: 51 * time.Minute, Gold: 2, Silver: 1},
+		{Rank: 2, Team: "SWE", Counted: []int{2, 5}, Time: 52 * time.Minute, Gold: 2},
+	}
+	if len(teams) != len(wantTeams) {
+		t.Fatalf("got %d teams, want %d: %+v", len(teams), len(wantTeams), teams)
+	}
+	for i, w := range wantTeams {
+		got := teams[i]
+		if got.Rank != w.Rank || got.Team != w.Team || got.Time != w.Time ||
+			got.Gold != w.Gold || got.Silver != w.Silver || got.Bronze != w.Bronze ||
+			len(got.Counted) != len(w.Counted) || got.Counted[0] != w.Counted[0] || got.Counted[1] != w.Counted[1] {
+			t.Errorf("team %d: got %+v, want %+v", i, got, w)
+		}
+	}
+
+	// With three counted times neither team is complete
+	teams = BuildTeamStandings(GroupStandings(reports), FieldNation, 3)
+	if teams[0].Rank != 1 || teams[0].Team != "NOR" || teams[1].Rank != 0 {
+		t.Errorf("Incomplete teams: got %+v", teams)
+	}
+	if got := teams[1].String(); got != "- SWE 00:52:00.000 2 1/0/0" {
+		t.Errorf("String: got %q", got)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("category, gender")
+	if err != nil || len(fields) != 2 || fields[0] != FieldCategory || fields[1] != FieldGender {
+		t.Errorf("ParseFields: got %v, %v", fields, err)
+	}
+	if _, err := ParseFields("category,age"); err == nil {
+		t.Errorf("ParseFields: expected error for unknown field")
+	}
+}
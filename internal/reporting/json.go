@@ -0,0 +1,180 @@
+package reporting
+
+import (
+	"encoding/json"
+)
+
+// jsonLapStat is the JSON representation of a LapStat.
+type jsonLapStat struct {
+	Duration string  `json:"duration"`
+	Speed    float64 `json:"speed"`
+}
+
+// jsonStage is the JSON representation of a single firing stage.
+type jsonStage struct {
+	Range   int    `json:"range"`
+	Hits    int    `json:"hits"`
+	Misses  int    `json:"misses"`
+	Card    string `json:"card"`
+	Targets []bool `json:"targets"`
+	// Arrival and Departure are split times measured from the scheduled start
+	Arrival   string `json:"arrival,omitempty"`
+	Departure string `json:"departure,omitempty"`
+	// Range analytics, see RangeStat
+	RangeTime   string `json:"rangeTime,omitempty"`
+	FirstHit    string `json:"firstHit,omitempty"`
+	HitInterval string `json:"hitInterval,omitempty"`
+}
+
+// jsonShooting is the JSON representation of the shooting statistics.
+type jsonShooting struct {
+	Hits   int         `json:"hits"`
+	Shots  int         `json:"shots"`
+	Stages []jsonStage `json:"stages"`
+}
+
+// jsonPenaltyLoops is the JSON representation of PenaltyLoops.
+// Skied, Skipped and Mismatch are zero if Known is false.
+type jsonPenaltyLoops struct {
+	Expected int  `json:"expected"`
+	Known    bool `json:"known"`
+	Skied    int  `json:"skied"`
+	Skipped  int  `json:"skipped"`
+	Mismatch bool `json:"mismatch"`
+}
+
+// jsonAthlete is the JSON representation of domain.Athlete.
+type jsonAthlete struct {
+	Bib      int    `json:"bib,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Nation   string `json:"nation,omitempty"`
+	Club     string `json:"club,omitempty"`
+	Gender   string `json:"gender,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// jsonReport is the JSON representation of a Report.
+// Field names are part of the output format and must stay stable.
+type jsonReport struct {
+	CompetitorID int `json:"competitorId"`
+	// Athlete is omitted if the race is run without a start list
+	Athlete   *jsonAthlete  `json:"athlete,omitempty"`
+	Status    string        `json:"status"`
+	TotalTime string        `json:"totalTime,omitempty"`
+	Laps      []jsonLapStat `json:"laps"`
+	// Penalty is omitted if the race format has no penalty loops
+	Penalty  *jsonLapStat `json:"penalty,omitempty"`
+	Shooting jsonShooting `json:"shooting"`
+	// PenaltyLoops is omitted if the competitor had no loops to ski and has not skied any
+	PenaltyLoops *jsonPenaltyLoops `json:"penaltyLoops,omitempty"`
+	TimePenalty  string            `json:"timePenalty,omitempty"`
+}
+
+// jsonStanding is the JSON representation of a Standing.
+type jsonStanding struct {
+	Rank          int    `json:"rank,omitempty"`
+	GapToLeader   string `json:"gapToLeader,omitempty"`
+	GapToPrevious string `json:"gapToPrevious,omitempty"`
+	jsonReport
+}
+
+func newJSONLapStat(s LapStat) jsonLapStat {
+	return jsonLapStat{
+		Duration: FormatDuration(s.Duration),
+		Speed:    s.AverageSpeed,
+	}
+}
+
+func newJSONReport(r Report) jsonReport {
+	jr := jsonReport{
+		CompetitorID: r.CompetitorID,
+		Status:       r.Status.String(),
+		Laps:         make([]jsonLapStat, 0, len(r.LapsStatistics)),
+		Shooting: jsonShooting{
+			Hits:   r.Shots,
+			Shots:  r.PossibleShots,
+			Stages: make([]jsonStage, 0, len(r.Shooting)),
+		},
+	}
+	if !r.NoPenaltyLaps {
+		penalty := newJSONLapStat(r.PenaltyLapStatictic)
+		jr.Penalty = &penalty
+	}
+	if !r.Athlete.IsZero() {
+		jr.Athlete = &jsonAthlete{
+			Bib:      r.Athlete.Bib,
+			Name:     r.Athlete.Name,
+			Nation:   r.Athlete.Nation,
+			Club:     r.Athlete.Club,
+			Gender:   r.Athlete.Gender,
+			Category: r.Athlete.Category,
+		}
+	}
+	if r.TotalTime > 0 {
+		jr.TotalTime = FormatDuration(r.TotalTime)
+	}
+	if r.PenaltyLoops.Known || r.PenaltyLoops.Expected > 0 {
+		jr.PenaltyLoops = &jsonPenaltyLoops{
+			Expected: r.PenaltyLoops.Expected,
+			Known:    r.PenaltyLoops.Known,
+			Skied:    r.PenaltyLoops.Skied,
+			Skipped:  r.PenaltyLoops.Skipped(),
+			Mismatch: r.PenaltyLoops.Mismatch(),
+		}
+	}
+	if r.TimePenalty > 0 {
+		jr.TimePenalty = FormatDuration(r.TimePenalty)
+	}
+	for _, lap := range r.LapsStatistics {
+		jr.Laps = append(jr.Laps, newJSONLapStat(lap))
+	}
+	rangeStats := CalculateRangeStats(r)
+	for i, stage := range r.Shooting {
+		js := jsonStage{
+			Range:   stage.Range,
+			Hits:    stage.Hits(),
+			Misses:  stage.Misses(),
+			Card:    stage.Card(),
+			Targets: stage.Targets[:],
+		}
+		if i < len(r.Splits) {
+			if r.Splits[i].Arrival > 0 {
+				js.Arrival = FormatDuration(r.Splits[i].Arrival)
+			}
+			if r.Splits[i].Departure > 0 {
+				js.Departure = FormatDuration(r.Splits[i].Departure)
+			}
+		}
+		if stat := rangeStats[i]; stat.RangeTime > 0 {
+			js.RangeTime = FormatDuration(stat.RangeTime)
+		}
+		if stat := rangeStats[i]; stat.TimeToFirstHit > 0 {
+			js.FirstHit = FormatDuration(stat.TimeToFirstHit)
+		}
+		if stat := rangeStats[i]; stat.HitInterval > 0 {
+			js.HitInterval = FormatDuration(stat.HitInterval)
+		}
+		jr.Shooting.Stages = append(jr.Shooting.Stages, js)
+	}
+	return jr
+}
+
+// MarshalJSON implements json.Marshaler.
+// Durations are encoded in the "HH:MM:SS.mmm" format, speeds in m/s.
+func (r Report) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJSONReport(r))
+}
+
+// MarshalJSON implements json.Marshaler.
+// Rank and gaps are omitted for competitors who did not finish or did not start.
+func (s Standing) MarshalJSON() ([]byte, error) {
+	js := jsonStanding{
+		Rank:       s.Rank,
+		jsonReport: newJSONReport(s.Report),
+	}
+	if s.Rank > 0 {
+		js.GapToLeader = FormatDuration(s.GapToLeader)
+		js.GapToPrevious = FormatDuration(s.GapToPrevious)
+	}
+	return json.Marshal(js)
+}
@@ -0,0 +1,79 @@
+package reporting
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestStanding_MarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		standing Standing
+		want     string
+	}{
+		{
+			name: "finished",
+			standing: Standing{
+				Rank:        2,
+				GapToLeader: 7*time.Second + 691*time.Millisecond,
+				Report: Report{
+					CompetitorID:        1,
+					Status:              domain.StatusFinished,
+					TotalTime:           25 * time.Minute,
+					LapsStatistics:      []LapStat{{Duration: 25 * time.Minute, AverageSpeed: 2.5}},
+					PenaltyLapStatictic: LapStat{Duration: time.Minute, AverageSpeed: 1.5},
+					Shots:               4,
+					PossibleShots:       5,
+					Shooting:            []domain.FiringStage{{Range: 1, Targets: [domain.TargetsPerStage]bool{true, true, false, true, true}}},
+				},
+			},
+			want: `{"rank":2,"gapToLeader":"00:00:07.691","gapToPrevious":"00:00:00.000","competitorId":1,"status":"Finished","totalTime":"00:25:00.000",` +
+				`"laps":[{"duration":"00:25:00.000","speed":2.5}],"penalty":{"duration":"00:01:00.000","speed":1.5},` +
+				`"shooting":{"hits":4,"shots":5,"stages":[{"range":1,"hits":4,"misses":1,"card":"XX-XX","targets":[true,true,false,true,true]}]}}`,
+		},
+		{
+			name:     "not started",
+			standing: Standing{Report: Report{CompetitorID: 2, Status: domain.StatusNotStarted}},
+			want: `{"competitorId":2,"status":"NotStarted","laps":[],"penalty":{"duration":"00:00:00.000","speed":0},` +
+				`"shooting":{"hits":0,"shots":0,"stages":[]}}`,
+		},
+		{
+			name: "skipped penalty loop",
+			standing: Standing{Report: Report{
+				CompetitorID: 3,
+				Status:       domain.StatusNotFinished,
+				PenaltyLoops: PenaltyLoops{Expected: 2, Skied: 1, Known: true},
+				TimePenalty:  2 * time.Minute,
+			}},
+			want: `{"competitorId":3,"status":"NotFinished","laps":[],"penalty":{"duration":"00:00:00.000","speed":0},` +
+				`"shooting":{"hits":0,"shots":0,"stages":[]},` +
+				`"penaltyLoops":{"expected":2,"known":true,"skied":1,"skipped":1,"mismatch":true},"timePenalty":"00:02:00.000"}`,
+		},
+		{
+			name: "unknown penalty loops",
+			standing: Standing{Report: Report{
+				CompetitorID: 4,
+				Status:       domain.StatusNotFinished,
+				PenaltyLoops: PenaltyLoops{Expected: 2},
+			}},
+			want: `{"competitorId":4,"status":"NotFinished","laps":[],"penalty":{"duration":"00:00:00.000","speed":0},` +
+				`"shooting":{"hits":0,"shots":0,"stages":[]},` +
+				`"penaltyLoops":{"expected":2,"known":false,"skied":0,"skipped":0,"mismatch":false}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tc.standing)
+			if err != nil {
+				t.Fatalf("json.Marshal failed: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("json.Marshal:\ngot:  %s\nwant: %s", got, tc.want)
+			}
+		})
+	}
+}
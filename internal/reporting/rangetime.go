@@ -0,0 +1,147 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// RangeStat holds the range analytics of a single firing stage.
+type RangeStat struct {
+	Stage int // Number of the firing stage in the race of the competitor, starting from 1
+	Range int // Number of the firing range
+	// RangeTime is the time from the arrival at the range to the departure, 0 while on the range.
+	RangeTime time.Duration
+	// TimeToFirstHit is the time from the arrival to the first hit, 0 without hits.
+	TimeToFirstHit time.Duration
+	// HitInterval is the average interval between consecutive hits, 0 with less than two hits.
+	HitInterval time.Duration
+}
+
+// CalculateRangeStats computes the range analytics of every firing stage of the report.
+func CalculateRangeStats(r Report) []RangeStat {
+	stats := make([]RangeStat, 0, len(r.Shooting))
+	for i, stage := range r.Shooting {
+		stat := RangeStat{Stage: i + 1, Range: stage.Range}
+		if !stage.Exit.IsZero() {
+			stat.RangeTime = stage.Exit.Sub(stage.Enter)
+		}
+		if n := len(stage.HitTimes); n > 0 {
+			stat.TimeToFirstHit = stage.HitTimes[0].Sub(stage.Enter)
+			if n > 1 {
+				stat.HitInterval = stage.HitTimes[n-1].Sub(stage.HitTimes[0]) / time.Duration(n-1)
+			}
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// RangeStanding is a single row of the "fastest on range" leaderboard.
+type RangeStanding struct {
+	// Rank is the place of the competitor. Competitors with equal results share a rank.
+	Rank         int
+	CompetitorID int
+	// Stages is the number of completed firing stages.
+	Stages int
+	// RangeTime is the total time spent on the range during completed stages.
+	RangeTime   time.Duration
+	GapToLeader time.Duration
+}
+
+// BuildRangeStandings builds the "fastest on range" leaderboard.
+// Competitors with more completed firing stages come first, then by ascending total range time.
+// Competitors who have not completed any stage are omitted.
+func BuildRangeStandings(reports []Report) []RangeStanding {
+	standings := make([]RangeStanding, 0, len(reports))
+	for _, r := range reports {
+		s := RangeStanding{CompetitorID: r.CompetitorID}
+		for _, stat := range CalculateRangeStats(r) {
+			if stat.RangeTime > 0 {
+				s.Stages++
+				s.RangeTime += stat.RangeTime
+			}
+		}
+		if s.Stages > 0 {
+			standings = append(standings, s)
+		}
+	}
+
+	slices.SortStableFunc(standings, compareRangeStandings)
+
+	for i := range standings {
+		switch {
+		case i == 0:
+			standings[i].Rank = 1
+		case compareRangeResults(standings[i], standings[i-1]) == 0:
+			standings[i].Rank = standings[i-1].Rank
+		default:
+			standings[i].Rank = i + 1
+		}
+		if standings[i].Stages == standings[0].Stages {
+			standings[i].GapToLeader = standings[i].RangeTime - standings[0].RangeTime
+		}
+	}
+	return standings
+}
+
+func compareRangeStandings(a, b RangeStanding) int {
+	if d := compareRangeResults(a, b); d != 0 {
+		return d
+	}
+	return a.CompetitorID - b.CompetitorID
+}
+
+// compareRangeResults compares the results of two competitors ignoring their IDs.
+func compareRangeResults(a, b RangeStanding) int {
+	if a.Stages != b.Stages {
+		return b.Stages - a.Stages
+	}
+	if a.RangeTime != b.RangeTime {
+		if a.RangeTime < b.RangeTime {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// String provides a string representation of the RangeStanding:
+// rank id range_time stages +gap_to_leader
+// The gap is omitted for competitors with fewer completed stages than the leader.
+func (s RangeStanding) String() string {
+	result := fmt.Sprintf("%d %d %s %d", s.Rank, s.CompetitorID, FormatDuration(s.RangeTime), s.Stages)
+	if s.Rank == 1 || s.GapToLeader > 0 {
+		result += " +" + FormatDuration(s.GapToLeader)
+	}
+	return result
+}
+
+// WriteRangeStandings writes the "fastest on range" leaderboard followed by
+// the range analytics of every competitor to w.
+func WriteRangeStandings(w io.Writer, reports []Report) error {
+	if _, err := fmt.Fprintln(w, "Fastest on range"); err != nil {
+		return err
+	}
+	for _, s := range BuildRangeStandings(reports) {
+		if _, err := fmt.Fprintln(w, s); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, "Range analytics"); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		for _, stat := range CalculateRangeStats(r) {
+			_, err := fmt.Fprintf(w, "%d stage %d range %d: %s on range, first hit %s, hit interval %s\n",
+				r.CompetitorID, stat.Stage, stat.Range,
+				FormatDuration(stat.RangeTime), FormatDuration(stat.TimeToFirstHit), FormatDuration(stat.HitInterval))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestCalculateRangeStats(t *testing.T) {
+	enter, _ := time.Parse("15:04:05.000", "10:05:00.000")
+	at := func(d time.Duration) time.Time { return enter.Add(d) }
+
+	r := Report{Shooting: []domain.FiringStage{
+		{Range: 1, Enter: enter, Exit: at(30 * time.Second), HitTimes: []time.Time{at(10 * time.Second), at(13 * time.Second), at(19 * time.Second)}},
+		{Range: 2, Enter: enter, HitTimes: []time.Time{at(12 * time.Second)}},
+	}}
+
+	got := CalculateRangeStats(r)
+	want := []RangeStat{
+		{Stage: 1, Range: 1, RangeTime: 30 * time.Second, TimeToFirstHit: 10 * time.Second, HitInterval: 4500 * time.Millisecond},
+		{Stage: 2, Range: 2, TimeToFirstHit: 12 * time.Second},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d stats, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stat %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBuildRangeStandings(t *testing.T) {
+	enter, _ := time.Parse("15:04:05.000", "10:05:00.000")
+	report := func(id int, rangeTimes ...time.Duration) Report {
+		r := Report{CompetitorID: id}
+		for _, d := range rangeTimes {
+			r.Shooting = append(r.Shooting, domain.FiringStage{Enter: enter, Exit: enter.Add(d)})
+		}
+		return r
+	}
+
+	got := BuildRangeStandings([]Report{
+		report(1, 30*time.Second, 35*time.Second),
+		report(2, 25*time.Second),
+		report(3, 32*time.Second, 33*time.Second),
+		report(4, 28*time.Second, 32*time.Second),
+		report(5),
+	})
+
+	want := []RangeStanding{
+		{Rank: 1, CompetitorID: 4, Stages: 2, RangeTime: 60 * time.Second},
+		{Rank: 2, CompetitorID: 1, Stages: 2, RangeTime: 65 * time.Second, GapToLeader: 5 * time.Second},
+		{Rank: 2, CompetitorID: 3, Stages: 2, RangeTime: 65 * time.Second, GapToLeader: 5 * time.Second},
+		// Fewer completed stages rank lower and have no gap
+		{Rank: 4, CompetitorID: 2, Stages: 1, RangeTime: 25 * time.Second},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d standings, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("standing %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if s := got[3].String(); s != "4 2 00:00:25.000 1" {
+		t.Errorf("String: got %q", s)
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // ShotsPerFiring defines the number of shots fired per firing session in a biathlon.
-const ShotsPerFiring = 5
+const ShotsPerFiring = domain.TargetsPerStage
 
 // LapTime holds statistics for a single lap (main or penalty).
 type LapStat struct {
@@ -20,25 +20,83 @@ type LapStat struct {
 // Report aggregates statistics for a single competitor's performance in the race.
 type Report struct {
 	CompetitorID        int
+	Athlete             domain.Athlete
 	Status              domain.Status
 	TotalTime           time.Duration
 	LapsStatistics      []LapStat
 	PenaltyLapStatictic LapStat
 	Shots               int
 	PossibleShots       int
+	Shooting            []domain.FiringStage
+	Splits              []Split // Split times at every firing stage, in the order of Shooting
+	PenaltyLoops        PenaltyLoops
+	// TimePenalty is the time added to TotalTime for skipped penalty loops.
+	TimePenalty time.Duration
+	// NoPenaltyLaps is set by the scoring rule of a race format without penalty loops,
+	// PenaltyLapStatictic and PenaltyLoops are not reported then.
+	NoPenaltyLaps bool
+}
+
+// PenaltyLoops reconciles the penalty loops skied by a competitor with the missed targets.
+//
+// The number of loops of a visit of the penalty area is taken from the comment of the leaving event,
+// or estimated from the time spent there if the config sets penaltyLoopTime.
+// Loops are reconciled only if the config sets penaltyLoopTime or the competitor has reported a count,
+// otherwise PenaltyLoops is zero and nothing is reported.
+// If some visit has no count, the number of skied loops is unknown: it is reported as such and never penalized.
+// Only the firing stages followed by a completed lap are reconciled, see domain.Competitor.ExpectedPenaltyLoops.
+type PenaltyLoops struct {
+	Expected int  // One loop for every missed target
+	Skied    int  // Valid only if Known is true
+	Known    bool // False if the number of skied loops of some visit of the penalty area is unknown
+}
+
+// Skipped returns the number of penalty loops the competitor has not skied, 0 if unknown.
+func (p PenaltyLoops) Skipped() int {
+	if !p.Known {
+		return 0
+	}
+	return max(0, p.Expected-p.Skied)
+}
+
+// Unknown reports whether the competitor had loops to ski but the number of skied loops is unknown.
+func (p PenaltyLoops) Unknown() bool {
+	return !p.Known && p.Expected > 0
+}
+
+// Mismatch reports whether the number of skied loops is known and differs from the expected one.
+func (p PenaltyLoops) Mismatch() bool {
+	return p.Known && p.Skied != p.Expected
+}
+
+// penaltyLoopsReported reports whether the competitor has reported the number of loops of some visit of the penalty area.
+func penaltyLoopsReported(c domain.Competitor) bool {
+	for _, lap := range c.PenaltyLaps {
+		if lap.Loops > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // CalculateReport generates a performance Report for a given competitor based on their race data and the configuration.
 func CalculateReport(c domain.Competitor, cfg *config.Config) Report {
 	r := Report{
 		CompetitorID:   c.ID,
+		Athlete:        c.Athlete,
 		LapsStatistics: make([]LapStat, 0, len(c.Laps)),
 		Shots:          c.Shots,
+		Shooting:       make([]domain.FiringStage, 0, len(c.FiringStages)),
+		Splits:         calculateSplits(c),
 	}
 
 	r.Status = c.Status
 
-	if len(c.Laps) == 0 || c.Laps[0].End.IsZero() {
+	for _, stage := range c.FiringStages {
+		r.Shooting = append(r.Shooting, stage.Clone())
+	}
+
+	if len(c.Laps) == 0 || c.Laps[len(c.Laps)-1].End.IsZero() {
 		r.TotalTime = 0
 	} else {
 		r.TotalTime = c.Laps[len(c.Laps)-1].End.Sub(c.ScheduledStart)
@@ -46,9 +104,19 @@ func CalculateReport(c domain.Competitor, cfg *config.Config) Report {
 
 	r.PossibleShots = c.FiringCount * ShotsPerFiring
 
+	if cfg.PenaltyLoopTime > 0 || penaltyLoopsReported(c) {
+		r.PenaltyLoops.Expected = c.ExpectedPenaltyLoops()
+		r.PenaltyLoops.Skied, r.PenaltyLoops.Known = c.SkiedPenaltyLoops()
+	}
+
 	currentLapStartTime := c.ScheduledStart
 	for _, lap := range c.Laps {
 		var lapStat LapStat
+		if lap.End.IsZero() {
+			// The lap has not been completed
+			r.LapsStatistics = append(r.LapsStatistics, lapStat)
+			continue
+		}
 		lapStat.Duration = lap.End.Sub(currentLapStartTime)
 		if lapStat.Duration > 0 {
 			lapStat.AverageSpeed = float64(cfg.LapLength) / lapStat.Duration.Seconds()
@@ -75,6 +143,13 @@ func CalculateReport(c domain.Competitor, cfg *config.Config) Report {
 		r.PenaltyLapStatictic.AverageSpeed = float64(cfg.PenaltyLength) / tottalPenaltyLapTime.Seconds()
 	}
 
+	rule, err := ScoringRuleFor(cfg.Format)
+	if err != nil {
+		// The format is checked by config.Validate
+		rule = scoreSprint
+	}
+	rule(&r, cfg)
+
 	return r
 }
 
@@ -89,7 +164,9 @@ func FormatDuration(d time.Duration) string {
 
 // String provides a compact string representation of the Report
 // conforming to the specified output format:
-// total_time id [{time_lap, avg}, ...] {time_penalty_lap, avg_penalty} shots/PossibleShots
+// total_time id [athlete] [{time_lap, avg}, ...] {time_penalty_lap, avg_penalty} shots/PossibleShots
+// A mismatch of penalty loops is flagged as [penalty loops skied/expected +time_penalty],
+// a time penalty for misses is shown as [time penalty +time_penalty].
 func (r Report) String() string {
 	var result string
 	switch r.Status {
@@ -97,12 +174,17 @@ func (r Report) String() string {
 		result += "[NotFinished]"
 	case domain.StatusNotStarted:
 		result += "[NotStarted]"
+	case domain.StatusRunning:
+		result += "[Running]"
 	case domain.StatusFinished:
 		result += FormatDuration(r.TotalTime)
 	}
 	result += " "
 
 	result += fmt.Sprintf("%d ", r.CompetitorID)
+	if !r.Athlete.IsZero() {
+		result += r.Athlete.String() + " "
+	}
 	result += " "
 
 	result += "["
@@ -115,10 +197,25 @@ func (r Report) String() string {
 	result += "]"
 	result += " "
 
-	result += fmt.Sprintf("{%s, %.2f}", FormatDuration(r.PenaltyLapStatictic.Duration), r.PenaltyLapStatictic.AverageSpeed)
-	result += " "
+	if !r.NoPenaltyLaps {
+		result += fmt.Sprintf("{%s, %.2f}", FormatDuration(r.PenaltyLapStatictic.Duration), r.PenaltyLapStatictic.AverageSpeed)
+		result += " "
+	}
 
 	result += fmt.Sprintf("%d/%d", r.Shots, r.PossibleShots)
 
+	switch {
+	case r.PenaltyLoops.Mismatch():
+		result += fmt.Sprintf(" [penalty loops %d/%d", r.PenaltyLoops.Skied, r.PenaltyLoops.Expected)
+		if r.TimePenalty > 0 {
+			result += " +" + FormatDuration(r.TimePenalty)
+		}
+		result += "]"
+	case r.PenaltyLoops.Unknown():
+		result += fmt.Sprintf(" [penalty loops ?/%d]", r.PenaltyLoops.Expected)
+	case r.TimePenalty > 0:
+		result += " [time penalty +" + FormatDuration(r.TimePenalty) + "]"
+	}
+
 	return result
 }
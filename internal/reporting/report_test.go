@@ -1,6 +1,7 @@
 package reporting
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -58,6 +59,31 @@ func TestCalculateReport(t *testing.T) {
 				PossibleShots: 10,
 			},
 		},
+		{
+			name: "not finished after first lap",
+			competitor: &domain.Competitor{
+				ID:             2,
+				Status:         domain.StatusNotFinished,
+				ScheduledStart: scheduledStart,
+				Laps: []domain.Lap{
+					{End: scheduledStart.Add(10 * time.Minute)},
+					{}, // The second lap has been started but not completed
+				},
+				Shots:       4,
+				FiringCount: 1,
+			},
+			wantReport: Report{
+				CompetitorID: 2,
+				Status:       domain.StatusNotFinished,
+				TotalTime:    0,
+				LapsStatistics: []LapStat{
+					{Duration: 10 * time.Minute, AverageSpeed: float64(cfg.LapLength) / (10 * 60)},
+					{},
+				},
+				Shots:         4,
+				PossibleShots: 5,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -106,3 +132,121 @@ func floatEquals(a, b, tolerance float64) bool {
 	}
 	return false
 }
+
+func TestCalculateReport_PenaltyLoops(t *testing.T) {
+	cfg := &config.Config{LapLength: 3000, PenaltyLength: 150, SkippedLoopPenalty: 2 * time.Minute}
+	scheduledStart := time.Date(2025, 6, 6, 10, 0, 0, 0, time.UTC)
+
+	newCompetitor := func(loops ...int) domain.Competitor {
+		c := domain.Competitor{
+			ID:             1,
+			Status:         domain.StatusFinished,
+			ScheduledStart: scheduledStart,
+			Laps:           []domain.Lap{{End: scheduledStart.Add(20 * time.Minute)}},
+			// Three misses on a completed stage, the last stage is still in progress
+			FiringStages: []domain.FiringStage{
+				{Range: 1, Enter: scheduledStart.Add(5 * time.Minute), Exit: scheduledStart.Add(6 * time.Minute), Targets: [domain.TargetsPerStage]bool{true, true}},
+				{Range: 2, Enter: scheduledStart.Add(15 * time.Minute)},
+			},
+		}
+		for i, l := range loops {
+			enter := scheduledStart.Add(6*time.Minute + time.Duration(i)*time.Minute)
+			c.PenaltyLaps = append(c.PenaltyLaps, domain.PenaltyLap{Start: enter, End: enter.Add(30 * time.Second), Loops: l})
+		}
+		return c
+	}
+
+	// The competitor has left the penalty area, but has not yet ended the lap
+	lapNotEnded := newCompetitor(2)
+	lapNotEnded.Status = domain.StatusRunning
+	lapNotEnded.Laps = []domain.Lap{{End: scheduledStart.Add(4 * time.Minute)}, {Start: scheduledStart.Add(4 * time.Minute)}}
+
+	testCases := []struct {
+		name          string
+		competitor    domain.Competitor
+		loopTime      time.Duration // Config penaltyLoopTime
+		want          PenaltyLoops
+		wantMismatch  bool
+		wantPenalty   time.Duration
+		wantTotalTime time.Duration
+		wantNote      string // Penalty loops note of the text report, empty if there is none
+	}{
+		{
+			name:          "all loops skied",
+			competitor:    newCompetitor(2, 1),
+			want:          PenaltyLoops{Expected: 3, Skied: 3, Known: true},
+			wantTotalTime: 20 * time.Minute,
+		},
+		{
+			name:          "skipped loop",
+			competitor:    newCompetitor(2),
+			want:          PenaltyLoops{Expected: 3, Skied: 2, Known: true},
+			wantMismatch:  true,
+			wantPenalty:   2 * time.Minute,
+			wantTotalTime: 22 * time.Minute,
+			wantNote:      " [penalty loops 2/3 +00:02:00.000]",
+		},
+		{
+			name:          "extra loop is flagged without penalty",
+			competitor:    newCompetitor(4),
+			want:          PenaltyLoops{Expected: 3, Skied: 4, Known: true},
+			wantMismatch:  true,
+			wantTotalTime: 20 * time.Minute,
+			wantNote:      " [penalty loops 4/3]",
+		},
+		{
+			name:          "unknown loops",
+			competitor:    newCompetitor(2, 0),
+			want:          PenaltyLoops{Expected: 3},
+			wantTotalTime: 20 * time.Minute,
+			wantNote:      " [penalty loops ?/3]",
+		},
+		{
+			name:          "no loop counts",
+			competitor:    newCompetitor(),
+			wantTotalTime: 20 * time.Minute,
+		},
+		{
+			name:          "penalty area not visited",
+			competitor:    newCompetitor(),
+			loopTime:      30 * time.Second,
+			want:          PenaltyLoops{Expected: 3, Known: true},
+			wantMismatch:  true,
+			wantPenalty:   6 * time.Minute,
+			wantTotalTime: 26 * time.Minute,
+			wantNote:      " [penalty loops 0/3 +00:06:00.000]",
+		},
+		{
+			name:       "loops are not due before the end of the lap",
+			competitor: lapNotEnded,
+			want:       PenaltyLoops{Known: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := *cfg
+			cfg.PenaltyLoopTime = tc.loopTime
+			got := CalculateReport(tc.competitor, &cfg)
+			if got.PenaltyLoops != tc.want {
+				t.Errorf("PenaltyLoops: got %+v, want %+v", got.PenaltyLoops, tc.want)
+			}
+			if got.PenaltyLoops.Mismatch() != tc.wantMismatch {
+				t.Errorf("Mismatch: got %v, want %v", got.PenaltyLoops.Mismatch(), tc.wantMismatch)
+			}
+			if got.TimePenalty != tc.wantPenalty {
+				t.Errorf("TimePenalty: got %v, want %v", got.TimePenalty, tc.wantPenalty)
+			}
+			if got.TotalTime != tc.wantTotalTime {
+				t.Errorf("TotalTime: got %v, want %v", got.TotalTime, tc.wantTotalTime)
+			}
+			var note string
+			if i := strings.Index(got.String(), " [penalty loops"); i >= 0 {
+				note = got.String()[i:]
+			}
+			if note != tc.wantNote {
+				t.Errorf("Text note: got %q, want %q", note, tc.wantNote)
+			}
+		})
+	}
+}
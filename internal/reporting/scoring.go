@@ -0,0 +1,65 @@
+package reporting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+)
+
+// ScoringRule applies the rules of a race format to a report calculated from the race data:
+// it adds time penalties to TotalTime and drops statistics which do not apply to the format.
+type ScoringRule func(r *Report, cfg *config.Config)
+
+// scoringRules maps race formats to their scoring rules.
+var scoringRules = map[config.RaceFormat]ScoringRule{
+	config.RaceSprint:     scoreSprint,
+	config.RaceIndividual: scoreIndividual,
+	// Competitors share the start time, so total times follow the finish order
+	config.RaceMassStart: scoreSprint,
+}
+
+// ScoringRuleFor returns the scoring rule of the race format.
+// The empty format is the default config.RaceSprint.
+func ScoringRuleFor(format config.RaceFormat) (ScoringRule, error) {
+	if format == "" {
+		format = config.RaceSprint
+	}
+	rule, ok := scoringRules[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown race format %q", format)
+	}
+	return rule, nil
+}
+
+// scoreSprint adds cfg.SkippedLoopPenalty for every penalty loop the competitor has not skied.
+func scoreSprint(r *Report, cfg *config.Config) {
+	if r.TotalTime <= 0 {
+		return
+	}
+	r.TimePenalty = time.Duration(r.PenaltyLoops.Skipped()) * cfg.SkippedLoopPenalty
+	r.TotalTime += r.TimePenalty
+}
+
+// scoreIndividual adds cfg.PenaltySeconds for every miss. Penalty loops are not skied in this format.
+func scoreIndividual(r *Report, cfg *config.Config) {
+	r.PenaltyLapStatictic = LapStat{}
+	r.PenaltyLoops = PenaltyLoops{}
+	r.NoPenaltyLaps = true
+	if r.TotalTime <= 0 {
+		return
+	}
+	r.TimePenalty = time.Duration(misses(*r)*cfg.PenaltySeconds) * time.Second
+	r.TotalTime += r.TimePenalty
+}
+
+// misses returns the number of targets missed on the firing stages the competitor has left.
+func misses(r Report) int {
+	n := 0
+	for _, stage := range r.Shooting {
+		if !stage.Exit.IsZero() {
+			n += stage.Misses()
+		}
+	}
+	return n
+}
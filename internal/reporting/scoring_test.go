@@ -0,0 +1,86 @@
+package reporting
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestCalculateReport_Scoring(t *testing.T) {
+	start := time.Date(2025, 6, 6, 10, 0, 0, 0, time.UTC)
+	competitor := domain.Competitor{
+		ID:             1,
+		Status:         domain.StatusFinished,
+		ScheduledStart: start,
+		Laps:           []domain.Lap{{End: start.Add(40 * time.Minute)}},
+		// Two misses on each of the completed stages
+		FiringStages: []domain.FiringStage{
+			{Range: 1, Enter: start.Add(10 * time.Minute), Exit: start.Add(11 * time.Minute), Targets: [domain.TargetsPerStage]bool{true, true, true}},
+			{Range: 2, Enter: start.Add(20 * time.Minute), Exit: start.Add(21 * time.Minute), Targets: [domain.TargetsPerStage]bool{false, true, true, true}},
+		},
+		PenaltyLaps: []domain.PenaltyLap{{Start: start.Add(11 * time.Minute), End: start.Add(12 * time.Minute), Loops: 4}},
+	}
+
+	testCases := []struct {
+		name          string
+		format        config.RaceFormat
+		wantTotalTime time.Duration
+		wantPenalty   LapStat
+		wantNoPenalty bool // The penalty block is left out of the text and JSON reports
+	}{
+		{
+			name:          "default",
+			wantTotalTime: 40 * time.Minute,
+			wantPenalty:   LapStat{Duration: time.Minute, AverageSpeed: 2.5},
+		},
+		{
+			name:          "sprint",
+			format:        config.RaceSprint,
+			wantTotalTime: 40 * time.Minute,
+			wantPenalty:   LapStat{Duration: time.Minute, AverageSpeed: 2.5},
+		},
+		{
+			name:          "individual",
+			format:        config.RaceIndividual,
+			wantTotalTime: 44 * time.Minute,
+			wantNoPenalty: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{LapLength: 5000, PenaltyLength: 150, Format: tc.format, PenaltySeconds: 60}
+			got := CalculateReport(competitor, cfg)
+			if got.TotalTime != tc.wantTotalTime {
+				t.Errorf("TotalTime: got %v, want %v", got.TotalTime, tc.wantTotalTime)
+			}
+			if got.TimePenalty != tc.wantTotalTime-40*time.Minute {
+				t.Errorf("TimePenalty: got %v, want %v", got.TimePenalty, tc.wantTotalTime-40*time.Minute)
+			}
+			if got.PenaltyLapStatictic != tc.wantPenalty {
+				t.Errorf("PenaltyLapStatictic: got %+v, want %+v", got.PenaltyLapStatictic, tc.wantPenalty)
+			}
+			if got.NoPenaltyLaps != tc.wantNoPenalty {
+				t.Errorf("NoPenaltyLaps: got %v, want %v", got.NoPenaltyLaps, tc.wantNoPenalty)
+			}
+			if hasBlock := strings.Contains(got.String(), "] {"); hasBlock == tc.wantNoPenalty {
+				t.Errorf("Text report %q: penalty block present %v", got.String(), hasBlock)
+			}
+			data, err := json.Marshal(Standing{Report: got})
+			if err != nil {
+				t.Fatalf("json.Marshal failed: %v", err)
+			}
+			if hasPenalty := strings.Contains(string(data), `"penalty":`); hasPenalty == tc.wantNoPenalty {
+				t.Errorf("JSON report %s: penalty present %v", data, hasPenalty)
+			}
+		})
+	}
+
+	if _, err := ScoringRuleFor("relay"); err == nil {
+		t.Errorf("ScoringRuleFor: expected error for unknown format")
+	}
+}
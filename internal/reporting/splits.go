@@ -0,0 +1,148 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// Split holds the times of a competitor at a single firing stage.
+// Times are measured from the scheduled start of the competitor.
+type Split struct {
+	Stage     int // Number of the firing stage in the race of the competitor, starting from 1
+	Range     int // Number of the firing range
+	Arrival   time.Duration
+	Departure time.Duration // 0 while the competitor is on the range
+}
+
+// SplitPoint selects the time of a Split used for ranking.
+type SplitPoint int
+
+const (
+	// SplitArrival ranks competitors by the arrival at the firing range.
+	SplitArrival SplitPoint = iota
+	// SplitDeparture ranks competitors by the departure from the firing range.
+	SplitDeparture
+)
+
+// String returns the name of the split point as used in the split standings.
+func (p SplitPoint) String() string {
+	switch p {
+	case SplitArrival:
+		return "arrival"
+	case SplitDeparture:
+		return "departure"
+	default:
+		return fmt.Sprintf("SplitPoint(%d)", int(p))
+	}
+}
+
+// time returns the time of the split at the given point, 0 if it has not been reached.
+func (s Split) time(point SplitPoint) time.Duration {
+	if point == SplitDeparture {
+		return s.Departure
+	}
+	return s.Arrival
+}
+
+// calculateSplits converts the firing stages of a competitor into splits.
+func calculateSplits(c domain.Competitor) []Split {
+	splits := make([]Split, 0, len(c.FiringStages))
+	for i, stage := range c.FiringStages {
+		split := Split{
+			Stage:   i + 1,
+			Range:   stage.Range,
+			Arrival: stage.Enter.Sub(c.ScheduledStart),
+		}
+		if !stage.Exit.IsZero() {
+			split.Departure = stage.Exit.Sub(c.ScheduledStart)
+		}
+		splits = append(splits, split)
+	}
+	return splits
+}
+
+// SplitStanding is a single row of the standings at a firing stage.
+type SplitStanding struct {
+	// Rank is the place of the competitor at the split. Competitors with equal times share a rank.
+	Rank         int
+	CompetitorID int
+	Split        Split
+	Time         time.Duration
+	GapToLeader  time.Duration
+}
+
+// BuildSplitStandings ranks competitors at the given firing stage (starting from 1)
+// by ascending time of the split point. Competitors who have not reached the point are omitted.
+func BuildSplitStandings(reports []Report, stage int, point SplitPoint) []SplitStanding {
+	standings := make([]SplitStanding, 0, len(reports))
+	for _, r := range reports {
+		if stage < 1 || stage > len(r.Splits) {
+			continue
+		}
+		split := r.Splits[stage-1]
+		if split.time(point) <= 0 {
+			continue
+		}
+		standings = append(standings, SplitStanding{CompetitorID: r.CompetitorID, Split: split, Time: split.time(point)})
+	}
+
+	slices.SortStableFunc(standings, func(a, b SplitStanding) int {
+		if a.Time != b.Time {
+			if a.Time < b.Time {
+				return -1
+			}
+			return 1
+		}
+		return a.CompetitorID - b.CompetitorID
+	})
+
+	for i := range standings {
+		switch {
+		case i == 0:
+			standings[i].Rank = 1
+		case standings[i].Time == standings[i-1].Time:
+			standings[i].Rank = standings[i-1].Rank
+		default:
+			standings[i].Rank = i + 1
+		}
+		standings[i].GapToLeader = standings[i].Time - standings[0].Time
+	}
+	return standings
+}
+
+// SplitStages returns the number of firing stages reached by at least one competitor.
+func SplitStages(reports []Report) int {
+	stages := 0
+	for _, r := range reports {
+		stages = max(stages, len(r.Splits))
+	}
+	return stages
+}
+
+// String provides a string representation of the SplitStanding:
+// rank id time +gap_to_leader
+func (s SplitStanding) String() string {
+	return fmt.Sprintf("%d %d %s +%s", s.Rank, s.CompetitorID, FormatDuration(s.Time), FormatDuration(s.GapToLeader))
+}
+
+// WriteSplitStandings writes the standings at the arrival and the departure of every firing stage to w,
+// each table preceded by a "Stage N arrival" or "Stage N departure" header.
+func WriteSplitStandings(w io.Writer, reports []Report) error {
+	for stage := 1; stage <= SplitStages(reports); stage++ {
+		for _, point := range []SplitPoint{SplitArrival, SplitDeparture} {
+			if _, err := fmt.Fprintf(w, "Stage %d %s\n", stage, point); err != nil {
+				return err
+			}
+			for _, s := range BuildSplitStandings(reports, stage, point) {
+				if _, err := fmt.Fprintln(w, s); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package reporting
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestBuildSplitStandings(t *testing.T) {
+	start, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	newCompetitor := func(id int, scheduled time.Duration, stages ...domain.FiringStage) domain.Competitor {
+		c := *domain.NewCompetitor(id)
+		c.ScheduledStart = start.Add(scheduled)
+		c.FiringStages = stages
+		return c
+	}
+	stage := func(enter, exit time.Duration) domain.FiringStage {
+		s := domain.FiringStage{Range: 1, Enter: start.Add(enter)}
+		if exit > 0 {
+			s.Exit = start.Add(exit)
+		}
+		return s
+	}
+
+	cfg := &config.Config{Laps: 2, LapLength: 3000}
+	var reports []Report
+	for _, c := range []domain.Competitor{
+		newCompetitor(1, 0, stage(5*time.Minute, 6*time.Minute), stage(12*time.Minute, 0)),
+		// Starts a minute later but arrives 20 seconds after competitor 1: faster on the split
+		newCompetitor(2, time.Minute, stage(5*time.Minute+20*time.Second, 6*time.Minute+50*time.Second)),
+		newCompetitor(3, 0, stage(5*time.Minute, 0)),
+		newCompetitor(4, 0),
+	} {
+		reports = append(reports, CalculateReport(c, cfg))
+	}
+
+	if got := SplitStages(reports); got != 2 {
+		t.Errorf("SplitStages: got %d, want 2", got)
+	}
+
+	type row struct {
+		rank, id int
+		time     time.Duration
+	}
+	testCases := []struct {
+		name  string
+		stage int
+		point SplitPoint
+		want  []row
+	}{
+		{
+			name:  "first arrival",
+			stage: 1,
+			point: SplitArrival,
+			want:  []row{{1, 2, 4*time.Minute + 20*time.Second}, {2, 1, 5 * time.Minute}, {2, 3, 5 * time.Minute}},
+		},
+		{
+			name:  "first departure skips competitors on the range",
+			stage: 1,
+			point: SplitDeparture,
+			want:  []row{{1, 2, 5*time.Minute + 50*time.Second}, {2, 1, 6 * time.Minute}},
+		},
+		{
+			name:  "second arrival",
+			stage: 2,
+			point: SplitArrival,
+			want:  []row{{1, 1, 12 * time.Minute}},
+		},
+		{
+			name:  "unknown stage",
+			stage: 3,
+			point: SplitArrival,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := BuildSplitStandings(reports, tc.stage, tc.point)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d standings, want %d: %+v", len(got), len(tc.want), got)
+			}
+			for i, w := range tc.want {
+				if got[i].Rank != w.rank || got[i].CompetitorID != w.id || got[i].Time != w.time {
+					t.Errorf("standing %d: got %+v, want %+v", i, got[i], w)
+				}
+				if gap := got[i].Time - got[0].Time; got[i].GapToLeader != gap {
+					t.Errorf("standing %d: gap got %v, want %v", i, got[i].GapToLeader, gap)
+				}
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSplitStandings(&buf, reports[:1]); err != nil {
+		t.Fatalf("WriteSplitStandings failed: %v", err)
+	}
+	want := "Stage 1 arrival\n1 1 00:05:00.000 +00:00:00.000\n" +
+		"Stage 1 departure\n1 1 00:06:00.000 +00:00:00.000\n" +
+		"Stage 2 arrival\n1 1 00:12:00.000 +00:00:00.000\n" +
+		"Stage 2 departure\n"
+	if buf.String() != want {
+		t.Errorf("WriteSplitStandings output mismatch:\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
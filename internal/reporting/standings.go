@@ -0,0 +1,111 @@
+package reporting
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// Standing is a single row of the final results table.
+type Standing struct {
+	// Rank is the place of the competitor. Competitors with equal times share a rank.
+	// Rank is 0 for competitors who did not finish or did not start.
+	Rank          int
+	Report        Report
+	GapToLeader   time.Duration
+	GapToPrevious time.Duration
+}
+
+// statusOrder defines the order of statuses in the standings.
+var statusOrder = map[domain.Status]int{
+	domain.StatusFinished:    0,
+	domain.StatusRunning:     1,
+	domain.StatusNotFinished: 2,
+	domain.StatusNotStarted:  3,
+}
+
+// BuildStandings orders the reports and assigns ranks and gaps.
+// Finishers are sorted by ascending TotalTime, followed by Running competitors
+// (more completed laps first, then by elapsed time), NotFinished and NotStarted competitors.
+// Ties within the same group are ordered by CompetitorID.
+func BuildStandings(reports []Report) []Standing {
+	sorted := slices.Clone(reports)
+	slices.SortStableFunc(sorted, compareReports)
+
+	standings := make([]Standing, 0, len(sorted))
+	var leader, previous *Report
+	for i, r := range sorted {
+		s := Standing{Report: r}
+		if r.Status == domain.StatusFinished {
+			switch {
+			case leader == nil:
+				s.Rank = 1
+				leader = &sorted[i]
+			case r.TotalTime == previous.TotalTime:
+				s.Rank = standings[i-1].Rank
+			default:
+				s.Rank = i + 1
+			}
+			s.GapToLeader = r.TotalTime - leader.TotalTime
+			if previous != nil {
+				s.GapToPrevious = r.TotalTime - previous.TotalTime
+			}
+			previous = &sorted[i]
+		}
+		standings = append(standings, s)
+	}
+
+	return standings
+}
+
+func compareReports(a, b Report) int {
+	if d := statusOrder[a.Status] - statusOrder[b.Status]; d != 0 {
+		return d
+	}
+	if a.Status == domain.StatusFinished && a.TotalTime != b.TotalTime {
+		if a.TotalTime < b.TotalTime {
+			return -1
+		}
+		return 1
+	}
+	if a.Status == domain.StatusRunning {
+		lapsA, elapsedA := progress(a)
+		lapsB, elapsedB := progress(b)
+		if lapsA != lapsB {
+			return lapsB - lapsA
+		}
+		if elapsedA != elapsedB {
+			if elapsedA < elapsedB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return a.CompetitorID - b.CompetitorID
+}
+
+// progress returns the number of completed laps and the time spent on them.
+func progress(r Report) (int, time.Duration) {
+	laps := 0
+	var elapsed time.Duration
+	for _, lap := range r.LapsStatistics {
+		if lap.Duration <= 0 {
+			break
+		}
+		laps++
+		elapsed += lap.Duration
+	}
+	return laps, elapsed
+}
+
+// String provides a string representation of the Standing:
+// rank report +gap_to_leader
+// Unranked competitors are marked with "-" and have no gap.
+func (s Standing) String() string {
+	if s.Rank == 0 {
+		return fmt.Sprintf("- %s", s.Report)
+	}
+	return fmt.Sprintf("%d %s +%s", s.Rank, s.Report, FormatDuration(s.GapToLeader))
+}
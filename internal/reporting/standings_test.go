@@ -0,0 +1,53 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestBuildStandings(t *testing.T) {
+	reports := []Report{
+		{CompetitorID: 1, Status: domain.StatusNotStarted},
+		{CompetitorID: 2, Status: domain.StatusFinished, TotalTime: 25 * time.Minute},
+		{CompetitorID: 3, Status: domain.StatusNotFinished},
+		{CompetitorID: 4, Status: domain.StatusFinished, TotalTime: 24 * time.Minute},
+		{CompetitorID: 5, Status: domain.StatusFinished, TotalTime: 25 * time.Minute},
+		{CompetitorID: 6, Status: domain.StatusFinished, TotalTime: 26 * time.Minute},
+	}
+
+	want := []struct {
+		id            int
+		rank          int
+		gapToLeader   time.Duration
+		gapToPrevious time.Duration
+	}{
+		{id: 4, rank: 1},
+		{id: 2, rank: 2, gapToLeader: time.Minute, gapToPrevious: time.Minute},
+		{id: 5, rank: 2, gapToLeader: time.Minute},
+		{id: 6, rank: 4, gapToLeader: 2 * time.Minute, gapToPrevious: time.Minute},
+		{id: 3, rank: 0},
+		{id: 1, rank: 0},
+	}
+
+	got := BuildStandings(reports)
+	if len(got) != len(want) {
+		t.Fatalf("BuildStandings length: got %d, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Report.CompetitorID != w.id {
+			t.Errorf("standings[%d].CompetitorID: got %d, want %d", i, g.Report.CompetitorID, w.id)
+		}
+		if g.Rank != w.rank {
+			t.Errorf("standings[%d].Rank: got %d, want %d", i, g.Rank, w.rank)
+		}
+		if g.GapToLeader != w.gapToLeader {
+			t.Errorf("standings[%d].GapToLeader: got %v, want %v", i, g.GapToLeader, w.gapToLeader)
+		}
+		if g.GapToPrevious != w.gapToPrevious {
+			t.Errorf("standings[%d].GapToPrevious: got %v, want %v", i, g.GapToPrevious, w.gapToPrevious)
+		}
+	}
+}
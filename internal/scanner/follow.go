@@ -0,0 +1,44 @@
+package scannerEvent
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// followReader is an io.Reader which waits for new data at the end of the input, like tail -f.
+type followReader struct {
+	ctx          context.Context
+	r            io.Reader
+	pollInterval time.Duration
+}
+
+// NewFollowScanner creates a Scanner which keeps reading a growing input.
+// At the end of the input it polls for new data every pollInterval.
+// Scan returns io.EOF only after ctx is done.
+func NewFollowScanner(ctx context.Context, r io.Reader, pollInterval time.Duration) *Scanner {
+	return NewScanner(&followReader{
+		ctx:          ctx,
+		r:            r,
+		pollInterval: pollInterval,
+	})
+}
+
+// Read reads data from the underlying reader and waits for more data at the end of the input.
+func (f *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := f.r.Read(p)
+		if n > 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		select {
+		case <-f.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(f.pollInterval):
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package scannerEvent
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestFollowScanner(t *testing.T) {
+	pr, pw := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := NewFollowScanner(ctx, pr, time.Millisecond)
+
+	go func() {
+		pw.Write([]byte("[09:05:59.867] 1 1\n"))
+		pw.Close() // The end of the input is reached, the scanner must keep waiting
+	}()
+
+	var e domain.Event
+	if err := sc.Scan(&e); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if e.ID != domain.EventCompetitorRegistered || e.CompetitorID != 1 {
+		t.Errorf("Scan: unexpected event %+v", e)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- sc.Scan(&e) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Scan returned %v before the context was cancelled", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != io.EOF {
+		t.Errorf("Scan after cancel: got %v, want io.EOF", err)
+	}
+}
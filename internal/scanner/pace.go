@@ -0,0 +1,108 @@
+package scannerEvent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+// EventScanner is a source of events, see task.ScannerEvent.
+type EventScanner interface {
+	Scan(*domain.Event) error
+}
+
+// SpeedMax replays events without pacing.
+const SpeedMax = 0
+
+// ParseSpeed converts a replay speed into a multiplier: "1x", "10x", "2.5" or "max" (SpeedMax).
+func ParseSpeed(s string) (float64, error) {
+	if s == "max" {
+		return SpeedMax, nil
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid speed %q", s)
+	}
+	return speed, nil
+}
+
+// PacedScanner replays events at the pace they happened.
+// The delay between events is the difference of their times divided by the speed,
+// measured from the moment the first event was read, so slow processing does not accumulate.
+type PacedScanner struct {
+	ctx    context.Context
+	source EventScanner
+	speed  float64
+
+	first   time.Time // Time of the first event
+	started time.Time // Wall time when the first event was read
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewPacedScanner creates a PacedScanner reading events from source.
+// With SpeedMax events are passed through without delay.
+// Scan returns io.EOF when ctx is done.
+func NewPacedScanner(ctx context.Context, source EventScanner, speed float64) *PacedScanner {
+	return &PacedScanner{
+		ctx:    ctx,
+		source: source,
+		speed:  speed,
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+// Scan reads the next event and waits until it is due.
+func (s *PacedScanner) Scan(e *domain.Event) error {
+	if s.ctx.Err() != nil {
+		return io.EOF
+	}
+	if err := s.source.Scan(e); err != nil {
+		return err
+	}
+	if s.speed == SpeedMax {
+		return nil
+	}
+
+	if s.started.IsZero() {
+		s.first = e.Time
+		s.started = s.now()
+		return nil
+	}
+
+	due := s.started.Add(time.Duration(float64(e.Time.Sub(s.first)) / s.speed))
+	if wait := due.Sub(s.now()); wait > 0 {
+		if err := s.sleep(s.ctx, wait); err != nil {
+			return io.EOF
+		}
+	}
+	return nil
+}
+
+// Line returns the input line of the last scanned event if the source tracks lines, otherwise 0.
+func (s *PacedScanner) Line() int {
+	if source, ok := s.source.(interface{ Line() int }); ok {
+		return source.Line()
+	}
+	return 0
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+package scannerEvent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestPacedScanner(t *testing.T) {
+	input := strings.Join([]string{
+		"[10:00:00.000] 1 1",
+		"[10:00:10.000] 1 2",
+		"[10:00:30.000] 1 3",
+	}, "\n")
+
+	testCases := []struct {
+		speed     float64
+		wantSleep []time.Duration
+	}{
+		{speed: 1, wantSleep: []time.Duration{10 * time.Second, 18 * time.Second}},
+		{speed: 10, wantSleep: []time.Duration{time.Second, 1800 * time.Millisecond}},
+		{speed: SpeedMax},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%gx", tc.speed), func(t *testing.T) {
+			clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			var slept []time.Duration
+
+			s := NewPacedScanner(context.Background(), NewScanner(strings.NewReader(input)), tc.speed)
+			s.now = func() time.Time { return clock }
+			s.sleep = func(_ context.Context, d time.Duration) error {
+				slept = append(slept, d)
+				clock = clock.Add(d)
+				return nil
+			}
+
+			for i := 0; ; i++ {
+				var e domain.Event
+				err := s.Scan(&e)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Scan failed: %v", err)
+				}
+				// Processing of the second event is slow, the next delay is shortened
+				if i == 1 {
+					clock = clock.Add(2 * time.Second / time.Duration(max(tc.speed, 1)))
+				}
+			}
+
+			if len(slept) != len(tc.wantSleep) {
+				t.Fatalf("slept %v, want %v", slept, tc.wantSleep)
+			}
+			for i := range slept {
+				if slept[i] != tc.wantSleep[i] {
+					t.Errorf("sleep %d: got %v, want %v", i, slept[i], tc.wantSleep[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPacedScanner_Cancel(t *testing.T) {
+	input := "[10:00:00.000] 1 1\n[11:00:00.000] 1 2\n"
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewPacedScanner(ctx, NewScanner(strings.NewReader(input)), 1)
+
+	var e domain.Event
+	if err := s.Scan(&e); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	cancel()
+	if err := s.Scan(&e); err != io.EOF {
+		t.Errorf("Scan after cancel: got %v, want io.EOF", err)
+	}
+}
+
+func TestParseSpeed(t *testing.T) {
+	for s, want := range map[string]float64{"1x": 1, "10x": 10, "2.5": 2.5, "max": SpeedMax} {
+		if got, err := ParseSpeed(s); err != nil || got != want {
+			t.Errorf("ParseSpeed(%q): got %v, %v, want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "0x", "-1", "fast"} {
+		if _, err := ParseSpeed(s); err == nil {
+			t.Errorf("ParseSpeed(%q): expected error", s)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 // Scanner is a struct that wraps a bufio.Scanner to read and parse event data.
 type Scanner struct {
 	scanner *bufio.Scanner
+	line    int
 }
 
 // NewScanner creates and returns a new Scanner.
@@ -32,18 +33,28 @@ func (s *Scanner) Scan(e *domain.Event) error {
 		return io.EOF
 	}
 
+	s.line++
 	line := s.scanner.Text()
-	return s.parseLine(line, e)
+	if err := ParseLine(line, e); err != nil {
+		return fmt.Errorf("line %d: %w", s.line, err)
+	}
+	return nil
+
+}
 
+// Line returns the number of the input line of the last scanned event, starting from 1.
+func (s *Scanner) Line() int {
+	return s.line
 }
 
-// parseLine parses a single line of event data and populates the given domain.Event.
+// ParseLine parses a single line of event data and populates the given domain.Event.
 // The line is expected to be in the format: "[HH:MM:SS.mmm] EventID ExtraParams [Comments]"
+// The race-closed sentinel may omit ExtraParams: "[HH:MM:SS.mmm] 12".
 // It returns an error if the line format is invalid or parsing fails.
-func (s *Scanner) parseLine(line string, e *domain.Event) error {
+func ParseLine(line string, e *domain.Event) error {
 	parts := strings.Fields(line) // Split the line into parts by whitespace.
-	if len(parts) < 3 {
-		return fmt.Errorf("invalid line format: %s", line) // Return an error if the line has fewer than 3 parts.
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid line format: %s", line) // Return an error if the line has fewer than 2 parts.
 	}
 
 	timeStr := strings.Trim(parts[0], "[]")                // Extract and trim the time string.
@@ -55,14 +66,36 @@ func (s *Scanner) parseLine(line string, e *domain.Event) error {
 	if err != nil {
 		return fmt.Errorf("invalid EventID: %w", err) // Return an error if EventID parsing fails.
 	}
-	extraParams, err := strconv.Atoi(parts[2]) // Parse the ExtraParams.
-	if err != nil {
-		return fmt.Errorf("invalid ExtraParams: %w", err) // Return an error if ExtraParams parsing fails.
+
+	extraParams := 0 // The race-closed sentinel is not related to a competitor.
+	if len(parts) > 2 {
+		extraParams, err = strconv.Atoi(parts[2]) // Parse the ExtraParams.
+		if err != nil {
+			return fmt.Errorf("invalid ExtraParams: %w", err) // Return an error if ExtraParams parsing fails.
+		}
+	} else if domain.EventID(eventID) != domain.EventRaceClosed {
+		return fmt.Errorf("invalid line format: %s", line) // Only the sentinel may omit ExtraParams.
 	}
+
 	// Populate the event fields.
 	e.Time = parsedTime
 	e.ID = domain.EventID(eventID)
 	e.CompetitorID = extraParams
-	e.Comments = strings.Join(parts[3:], " ") // Join the remaining parts as comments.
+	e.Comments = ""
+	if len(parts) > 3 {
+		e.Comments = strings.Join(parts[3:], " ") // Join the remaining parts as comments.
+	}
 	return nil
 }
+
+// FormatLine formats the event as a line of event data, the inverse of ParseLine.
+func FormatLine(e domain.Event) string {
+	if e.ID == domain.EventRaceClosed && e.CompetitorID == 0 && e.Comments == "" {
+		return fmt.Sprintf("[%s] %d", e.Time.Format("15:04:05.000"), e.ID)
+	}
+	line := fmt.Sprintf("[%s] %d %d", e.Time.Format("15:04:05.000"), e.ID, e.CompetitorID)
+	if e.Comments != "" {
+		line += " " + e.Comments
+	}
+	return line
+}
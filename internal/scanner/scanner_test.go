@@ -0,0 +1,62 @@
+package scannerEvent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestParseLine(t *testing.T) {
+	at, _ := time.Parse("15:04:05.000", "10:30:00.000")
+
+	tests := []struct {
+		name    string
+		line    string
+		want    domain.Event
+		wantErr bool
+	}{
+		{
+			name: "Comments",
+			line: "[10:30:00.000] 11 1 Lost in the forest",
+			want: domain.Event{Time: at, ID: domain.EventCompetitorCanNotContinue, CompetitorID: 1, Comments: "Lost in the forest"},
+		},
+		{
+			name: "RaceClosedWithoutCompetitor",
+			line: "[10:30:00.000] 12",
+			want: domain.Event{Time: at, ID: domain.EventRaceClosed},
+		},
+		{
+			name: "RaceClosedWithCompetitor",
+			line: "[10:30:00.000] 12 0",
+			want: domain.Event{Time: at, ID: domain.EventRaceClosed},
+		},
+		{name: "MissingCompetitor", line: "[10:30:00.000] 4", wantErr: true},
+		{name: "InvalidTime", line: "[10:30] 4 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.Event
+			err := ParseLine(tt.line, &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLine failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLine: got %+v, want %+v", got, tt.want)
+			}
+
+			// FormatLine is the inverse of ParseLine
+			var again domain.Event
+			if err := ParseLine(FormatLine(got), &again); err != nil || again != got {
+				t.Errorf("Round trip of %q: got %+v, %v", FormatLine(got), again, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package server exposes the live state of a race over HTTP as JSON.
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+	"github.com/Valery223/biathlon-test/internal/task"
+)
+
+// Server is an http.Handler serving the state of a race.
+//
+//	GET  /competitors       reports of all competitors
+//	GET  /competitors/{id}  report of a single competitor
+//	GET  /standings         current standings
+//	GET  /events?since=N    output log starting from the event with sequence number N
+//	POST /events            incoming events, one per line in the README format
+//	GET  /stream            Server-Sent Events with events, finishes, disqualifications and standings
+type Server struct {
+	race *task.Race
+	mux  *http.ServeMux
+}
+
+// New creates a Server for the given race.
+func New(race *task.Race) *Server {
+	s := &Server{
+		race: race,
+		mux:  http.NewServeMux(),
+	}
+	s.mux.HandleFunc("GET /competitors", s.handleCompetitors)
+	s.mux.HandleFunc("GET /competitors/{id}", s.handleCompetitor)
+	s.mux.HandleFunc("GET /standings", s.handleStandings)
+	s.mux.HandleFunc("GET /events", s.handleEvents)
+	s.mux.HandleFunc("POST /events", s.handlePostEvents)
+	s.mux.HandleFunc("GET /stream", s.handleStream)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// eventView is the JSON representation of an event of the output log.
+type eventView struct {
+	Seq          int            `json:"seq"`
+	Time         string         `json:"time"`
+	ID           domain.EventID `json:"id"`
+	CompetitorID int            `json:"competitorId"`
+	Comments     string         `json:"comments,omitempty"`
+	Text         string         `json:"text"`
+}
+
+func newEventView(seq int, e domain.Event) eventView {
+	return eventView{
+		Seq:          seq,
+		Time:         e.Time.Format("15:04:05.000"),
+		ID:           e.ID,
+		CompetitorID: e.CompetitorID,
+		Comments:     e.Comments,
+		Text:         e.Format(),
+	}
+}
+
+func (s *Server) handleCompetitors(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.race.Reports())
+}
+
+func (s *Server) handleCompetitor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid competitor id %q", r.PathValue("id")))
+		return
+	}
+	report, ok := s.race.Report(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("competitor %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) handleStandings(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.race.Standings())
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	since := 0
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		since, err = strconv.Atoi(v)
+		if err != nil || since < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid parameter since %q", v))
+			return
+		}
+	}
+
+	events := s.race.Events(since)
+	views := make([]eventView, 0, len(events))
+	for i, e := range events {
+		views = append(views, newEventView(since+i, e))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// maxPostEventsSize is the limit of the body of POST /events in bytes.
+const maxPostEventsSize = 1 << 20
+
+// postEventsResponse is the response to POST /events.
+type postEventsResponse struct {
+	Accepted int      `json:"accepted"`
+	Events   []string `json:"events"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// handlePostEvents processes the incoming events from the request body.
+// Events are processed in order until the first invalid one.
+// A body larger than maxPostEventsSize is rejected after the events preceding the limit.
+func (s *Server) handlePostEvents(w http.ResponseWriter, r *http.Request) {
+	resp := postEventsResponse{Events: make([]string, 0)}
+	sc := bufio.NewScanner(http.MaxBytesReader(w, r.Body, maxPostEventsSize))
+
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+
+		event := &domain.Event{}
+		if err := scannerEvent.ParseLine(text, event); err != nil {
+			resp.Error = fmt.Sprintf("line %d: %v", line, err)
+			writeJSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		events, err := s.race.Process(event)
+		for _, e := range events {
+			resp.Events = append(resp.Events, e.Format())
+		}
+		if err != nil {
+			resp.Error = fmt.Sprintf("line %d: %v", line, err)
+			writeJSON(w, http.StatusUnprocessableEntity, resp)
+			return
+		}
+		resp.Accepted++
+	}
+	if err := sc.Err(); err != nil {
+		resp.Error = err.Error()
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeJSON(w, status, resp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
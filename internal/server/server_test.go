@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/task"
+)
+
+func TestServer(t *testing.T) {
+	cfg := &config.Config{Laps: 1, LapLength: 3000, PenaltyLength: 150, FiringLines: 1, StartDelta: 30 * time.Second}
+	srv := New(task.NewRace(cfg))
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	events := strings.Join([]string{
+		"[09:00:00.000] 1 1",
+		"[09:01:00.000] 2 1 10:00:00.000",
+		"[10:00:01.000] 4 1",
+		"[10:10:00.000] 10 1",
+	}, "\n")
+	if rec := do(http.MethodPost, "/events", events); rec.Code != http.StatusOK {
+		t.Fatalf("POST /events: got status %d, body %s", rec.Code, rec.Body)
+	}
+
+	t.Run("InvalidEvent", func(t *testing.T) {
+		rec := do(http.MethodPost, "/events", "[10:11:00.000] 4 1")
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("POST /events: got status %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+		}
+	})
+
+	t.Run("BodyTooLarge", func(t *testing.T) {
+		rec := do(http.MethodPost, "/events", strings.Repeat("\n", maxPostEventsSize+1))
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("POST /events: got status %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+		}
+	})
+
+	t.Run("Standings", func(t *testing.T) {
+		rec := do(http.MethodGet, "/standings", "")
+		var got []struct {
+			Rank         int    `json:"rank"`
+			CompetitorID int    `json:"competitorId"`
+			TotalTime    string `json:"totalTime"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("GET /standings: invalid body %s: %v", rec.Body, err)
+		}
+		if len(got) != 1 || got[0].Rank != 1 || got[0].CompetitorID != 1 || got[0].TotalTime != "00:10:00.000" {
+			t.Errorf("GET /standings: unexpected body %s", rec.Body)
+		}
+	})
+
+	t.Run("Competitor", func(t *testing.T) {
+		if rec := do(http.MethodGet, "/competitors/1", ""); rec.Code != http.StatusOK {
+			t.Errorf("GET /competitors/1: got status %d", rec.Code)
+		}
+		if rec := do(http.MethodGet, "/competitors/2", ""); rec.Code != http.StatusNotFound {
+			t.Errorf("GET /competitors/2: got status %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("EventsSince", func(t *testing.T) {
+		rec := do(http.MethodGet, "/events?since=3", "")
+		var got []eventView
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("GET /events: invalid body %s: %v", rec.Body, err)
+		}
+		// The lap end and the generated finish event
+		if len(got) != 2 || got[0].Seq != 3 || got[1].Text != "[10:10:00.000] The competitor(1) has finished, last lap 00:10:00.000" {
+			t.Errorf("GET /events?since=3: unexpected body %s", rec.Body)
+		}
+	})
+}
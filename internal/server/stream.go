@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/task"
+)
+
+// keepAliveInterval is the interval of comments sent to keep idle SSE connections open.
+const keepAliveInterval = 15 * time.Second
+
+// handleStream pushes race messages to the client as Server-Sent Events.
+// The SSE event name is the message kind, the data is the event view or the standings.
+// If the client is too slow to keep up, the hub drops the subscription and the stream ends,
+// the client can reconnect and catch up with GET /events?since=N.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	sub := s.race.Subscribe()
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case m, ok := <-sub.C():
+			if !ok {
+				return
+			}
+			if err := writeMessage(w, m); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeMessage writes a single message in the SSE format.
+func writeMessage(w io.Writer, m task.Message) error {
+	var data any = newEventView(m.Seq, m.Event)
+	if m.Kind == task.MessageStandings {
+		data = m.Standings
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if m.Kind != task.MessageStandings {
+		// The id lets the client catch up with GET /events?since=N
+		if _, err := fmt.Fprintf(w, "id: %d\n", m.Seq); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.Kind, b)
+	return err
+}
@@ -0,0 +1,340 @@
+// Package sink provides destinations for the output log and the reports of a task:
+// writers (stdout, files), in-memory recorders and fan-out to several sinks.
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+)
+
+// EventSink receives the events of the output log.
+type EventSink interface {
+	WriteEvent(e domain.Event) error
+}
+
+// ReportSink receives the standings of the race.
+type ReportSink interface {
+	// WriteIntermediate is called with the current standings during the race.
+	WriteIntermediate(at time.Time, standings []reporting.Standing) error
+	// WriteFinal is called once with the final standings.
+	WriteFinal(standings []reporting.Standing) error
+}
+
+// EventWriter writes events to an io.Writer in the output log format, one per line.
+type EventWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewEventWriter creates an EventWriter writing to w.
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{w: w}
+}
+
+// Stdout returns an EventWriter writing to the standard output.
+func Stdout() *EventWriter {
+	return NewEventWriter(os.Stdout)
+}
+
+// WriteEvent implements EventSink.
+func (s *EventWriter) WriteEvent(e domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintln(s.w, e.Format())
+	return err
+}
+
+// ReportWriter writes standings to an io.Writer in the given report format.
+// In the text format the standings are surrounded by headers as in the original output.
+// Intermediate standings are written in the text format only: the JSON and CSV output
+// must hold a single document, the final standings.
+type ReportWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format reporting.Format
+	cfg    *config.Config
+}
+
+// NewReportWriter creates a ReportWriter writing to w.
+func NewReportWriter(w io.Writer, format reporting.Format, cfg *config.Config) *ReportWriter {
+	return &ReportWriter{w: w, format: format, cfg: cfg}
+}
+
+// WriteIntermediate implements ReportSink.
+func (s *ReportWriter) WriteIntermediate(at time.Time, standings []reporting.Standing) error {
+	if s.format != reporting.FormatText {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprintf(s.w, "Intermediate standings at %s\n", at.Format("15:04:05.000")); err != nil {
+		return err
+	}
+	return reporting.WriteStandings(s.w, standings, s.format, s.cfg)
+}
+
+// WriteFinal implements ReportSink.
+func (s *ReportWriter) WriteFinal(standings []reporting.Standing) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.format != reporting.FormatText {
+		return reporting.WriteStandings(s.w, standings, s.format, s.cfg)
+	}
+
+	if _, err := fmt.Fprintln(s.w, "Final reports"); err != nil {
+		return err
+	}
+	if err := reporting.WriteStandings(s.w, standings, s.format, s.cfg); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(s.w, "End of task")
+	return err
+}
+
+// SplitsWriter writes the per-lap splits of the final standings in the long CSV format.
+// Intermediate standings are ignored.
+type SplitsWriter struct {
+	w io.Writer
+}
+
+// NewSplitsWriter creates a SplitsWriter writing to w.
+func NewSplitsWriter(w io.Writer) *SplitsWriter {
+	return &SplitsWriter{w: w}
+}
+
+// WriteIntermediate implements ReportSink.
+func (s *SplitsWriter) WriteIntermediate(time.Time, []reporting.Standing) error {
+	return nil
+}
+
+// WriteFinal implements ReportSink.
+func (s *SplitsWriter) WriteFinal(standings []reporting.Standing) error {
+	return reporting.WriteSplitsCSV(s.w, standings)
+}
+
+// SplitStandingsWriter writes the standings at every firing stage of the final standings.
+// Intermediate standings are ignored.
+type SplitStandingsWriter struct {
+	w io.Writer
+}
+
+// NewSplitStandingsWriter creates a SplitStandingsWriter writing to w.
+func NewSplitStandingsWriter(w io.Writer) *SplitStandingsWriter {
+	return &SplitStandingsWriter{w: w}
+}
+
+// WriteIntermediate implements ReportSink.
+func (s *SplitStandingsWriter) WriteIntermediate(time.Time, []reporting.Standing) error {
+	return nil
+}
+
+// WriteFinal implements ReportSink.
+func (s *SplitStandingsWriter) WriteFinal(standings []reporting.Standing) error {
+	return reporting.WriteSplitStandings(s.w, standingReports(standings))
+}
+
+// RangeStandingsWriter writes the "fastest on range" leaderboard and the range analytics
+// of the final standings. Intermediate standings are ignored.
+type RangeStandingsWriter struct {
+	w io.Writer
+}
+
+// NewRangeStandingsWriter creates a RangeStandingsWriter writing to w.
+func NewRangeStandingsWriter(w io.Writer) *RangeStandingsWriter {
+	return &RangeStandingsWriter{w: w}
+}
+
+// WriteIntermediate implements ReportSink.
+func (s *RangeStandingsWriter) WriteIntermediate(time.Time, []reporting.Standing) error {
+	return nil
+}
+
+// WriteFinal implements ReportSink.
+func (s *RangeStandingsWriter) WriteFinal(standings []reporting.Standing) error {
+	return reporting.WriteRangeStandings(s.w, standingReports(standings))
+}
+
+// GroupsWriter writes the standings per group and the team aggregate table of the final standings.
+// Intermediate standings are ignored.
+type GroupsWriter struct {
+	w      io.Writer
+	fields []reporting.Field
+	team   reporting.Field
+	bestN  int
+}
+
+// NewGroupsWriter creates a GroupsWriter writing to w.
+// The standings are grouped by fields, the group tables are omitted without fields.
+// The team table aggregates the best bestN times by the team field, it is omitted if team is empty.
+func NewGroupsWriter(w io.Writer, fields []reporting.Field, team reporting.Field, bestN int) *GroupsWriter {
+	return &GroupsWriter{w: w, fields: fields, team: team, bestN: bestN}
+}
+
+// WriteIntermediate implements ReportSink.
+func (s *GroupsWriter) WriteIntermediate(time.Time, []reporting.Standing) error {
+	return nil
+}
+
+// WriteFinal implements ReportSink.
+func (s *GroupsWriter) WriteFinal(standings []reporting.Standing) error {
+	groups := reporting.GroupStandings(standingReports(standings), s.fields...)
+	if len(s.fields) > 0 {
+		if err := reporting.WriteGroups(s.w, groups); err != nil {
+			return err
+		}
+	}
+	if s.team == "" {
+		return nil
+	}
+	teams := reporting.BuildTeamStandings(groups, s.team, s.bestN)
+	return reporting.WriteTeamStandings(s.w, teams, s.team, s.bestN)
+}
+
+// standingReports returns the reports of the standings in the same order.
+func standingReports(standings []reporting.Standing) []reporting.Report {
+	reports := make([]reporting.Report, 0, len(standings))
+	for _, st := range standings {
+		reports = append(reports, st.Report)
+	}
+	return reports
+}
+
+// FileEventSink writes events to a file in the output log format.
+// It must be closed when the task has finished.
+type FileEventSink struct {
+	*EventWriter
+	f *os.File
+}
+
+// NewFileEventSink creates or truncates the file at path.
+func NewFileEventSink(path string) (*FileEventSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output log file: %w", err)
+	}
+	return &FileEventSink{EventWriter: NewEventWriter(f), f: f}, nil
+}
+
+// Close closes the file.
+func (s *FileEventSink) Close() error {
+	return s.f.Close()
+}
+
+// Memory records events and standings in memory, mainly for tests and embedding applications.
+// It implements both EventSink and ReportSink.
+type Memory struct {
+	mu           sync.Mutex
+	events       []domain.Event
+	intermediate [][]reporting.Standing
+	final        []reporting.Standing
+}
+
+// NewMemory creates an empty Memory sink.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// WriteEvent implements EventSink.
+func (m *Memory) WriteEvent(e domain.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, e)
+	return nil
+}
+
+// WriteIntermediate implements ReportSink.
+func (m *Memory) WriteIntermediate(_ time.Time, standings []reporting.Standing) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.intermediate = append(m.intermediate, standings)
+	return nil
+}
+
+// WriteFinal implements ReportSink.
+func (m *Memory) WriteFinal(standings []reporting.Standing) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.final = standings
+	return nil
+}
+
+// Events returns the recorded events.
+func (m *Memory) Events() []domain.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]domain.Event(nil), m.events...)
+}
+
+// Intermediate returns the recorded intermediate standings.
+func (m *Memory) Intermediate() [][]reporting.Standing {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([][]reporting.Standing(nil), m.intermediate...)
+}
+
+// Final returns the final standings, or nil if they have not been written yet.
+func (m *Memory) Final() []reporting.Standing {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.final
+}
+
+// multiEventSink writes every event to all of its sinks.
+type multiEventSink []EventSink
+
+// MultiEventSink returns an EventSink writing to all the given sinks.
+// All sinks receive the event even if some of them fail, the errors are joined.
+func MultiEventSink(sinks ...EventSink) EventSink {
+	return multiEventSink(sinks)
+}
+
+func (m multiEventSink) WriteEvent(e domain.Event) error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.WriteEvent(e))
+	}
+	return errors.Join(errs...)
+}
+
+// multiReportSink writes standings to all of its sinks.
+type multiReportSink []ReportSink
+
+// MultiReportSink returns a ReportSink writing to all the given sinks.
+// All sinks receive the standings even if some of them fail, the errors are joined.
+func MultiReportSink(sinks ...ReportSink) ReportSink {
+	return multiReportSink(sinks)
+}
+
+func (m multiReportSink) WriteIntermediate(at time.Time, standings []reporting.Standing) error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.WriteIntermediate(at, standings))
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiReportSink) WriteFinal(standings []reporting.Standing) error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.WriteFinal(standings))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,81 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+)
+
+type failingSink struct{}
+
+func (failingSink) WriteEvent(domain.Event) error { return errors.New("write failed") }
+
+func TestMultiEventSink(t *testing.T) {
+	var buf bytes.Buffer
+	memory := NewMemory()
+	s := MultiEventSink(failingSink{}, NewEventWriter(&buf), memory)
+
+	eventTime, _ := time.Parse("15:04:05.000", "09:30:01.005")
+	e := domain.Event{Time: eventTime, ID: domain.EventCompetitorRegistered, CompetitorID: 1}
+	if err := s.WriteEvent(e); err == nil {
+		t.Error("Expected the error of the failing sink")
+	}
+
+	// The other sinks receive the event anyway
+	if got, want := buf.String(), "[09:30:01.005] The competitor(1) registered\n"; got != want {
+		t.Errorf("EventWriter output mismatch: got %q, want %q", got, want)
+	}
+	if got := memory.Events(); len(got) != 1 || got[0] != e {
+		t.Errorf("Memory events mismatch: got %+v", got)
+	}
+}
+
+func TestReportWriter_Text(t *testing.T) {
+	cfg := &config.Config{Laps: 1, LapLength: 3000, PenaltyLength: 150}
+	standings := reporting.BuildStandings([]reporting.Report{{CompetitorID: 1, Status: domain.StatusNotStarted}})
+
+	var buf bytes.Buffer
+	w := NewReportWriter(&buf, reporting.FormatText, cfg)
+	at, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	if err := w.WriteIntermediate(at, standings); err != nil {
+		t.Fatalf("WriteIntermediate failed: %v", err)
+	}
+	if err := w.WriteFinal(standings); err != nil {
+		t.Fatalf("WriteFinal failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Intermediate standings at 10:00:00.000\n", "Final reports\n", "End of task\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output %q does not contain %q", out, want)
+		}
+	}
+}
+
+func TestReportWriter_JSON(t *testing.T) {
+	cfg := &config.Config{Laps: 1, LapLength: 3000, PenaltyLength: 150}
+	standings := reporting.BuildStandings([]reporting.Report{{CompetitorID: 1, Status: domain.StatusNotStarted}})
+
+	var buf bytes.Buffer
+	w := NewReportWriter(&buf, reporting.FormatJSON, cfg)
+	at, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	if err := w.WriteIntermediate(at, standings); err != nil {
+		t.Fatalf("WriteIntermediate failed: %v", err)
+	}
+	if err := w.WriteFinal(standings); err != nil {
+		t.Fatalf("WriteFinal failed: %v", err)
+	}
+
+	// The final standings are the only document
+	var reports []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &reports); err != nil {
+		t.Errorf("Output is not a JSON document: %v\n%s", err, buf.String())
+	}
+}
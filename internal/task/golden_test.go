@@ -0,0 +1,168 @@
+package task
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/registry"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+	"github.com/Valery223/biathlon-test/internal/sink"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// TestTask_Golden runs every case in testdata through the Task and compares the output
+// with the golden file. A case is a directory with config.json, events and output.golden,
+// which holds the output log followed by the final report as printed by the CLI.
+// With start_list.csv in the directory events for competitors missing from it are rejected.
+//
+// Run "go test ./internal/task -run Golden -update" to regenerate the golden files.
+func TestTask_Golden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "*", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) == 0 {
+		t.Fatal("No cases in testdata")
+	}
+
+	for _, cfgPath := range dirs {
+		dir := filepath.Dir(cfgPath)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			got := runGolden(t, dir)
+
+			goldenPath := filepath.Join(dir, "output.golden")
+			if *update {
+				if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("Failed to read golden file, run with -update to create it: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Output mismatch with %s, run with -update if the change is intended:\n%s", goldenPath, diffLines(string(want), string(got)))
+			}
+		})
+	}
+}
+
+// TestTask_ReadmeExample checks the readme_example case against the example of the README:
+// the output log must match, the final report must match up to the deviations listed below.
+func TestTask_ReadmeExample(t *testing.T) {
+	readme, err := os.ReadFile(filepath.Join("..", "..", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := os.ReadFile(filepath.Join("testdata", "readme_example", "output.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotLog, gotReport, ok := strings.Cut(string(golden), "Final reports\n")
+	if !ok {
+		t.Fatal("No final report in the golden file")
+	}
+
+	if wantLog := readmeBlock(t, string(readme), "`Output log`"); gotLog != wantLog {
+		t.Errorf("Output log mismatch with README.md:\n%s", diffLines(wantLog, gotLog))
+	}
+
+	// Known deviations of the final report from the README resulting table
+	deviations := []struct {
+		readme, golden, reason string
+	}{
+		{"[NotFinished] 1 [", "- [NotFinished] 1  [", "standings start with the rank, the baseline prints two spaces after the competitor ID"},
+		{"{00:29:03.872, 2.093}", "{00:29:03.872 2.094}", "lap statistics are printed as in the baseline, 3651 m in 1743.872 s is 2.0936 m/s"},
+		{"{,}", "{00:00:00.000 0.000}", "the baseline prints laps which are not completed as zero"},
+		{"{00:01:44.296, 0.481}", "{00:01:52.476, 0.44}", "the README penalty time does not follow from its events, 09:49:55.915 to 09:51:48.391 is 00:01:52.476"},
+	}
+	want := readmeBlock(t, string(readme), "`Resulting table`")
+	for _, d := range deviations {
+		if !strings.Contains(want, d.readme) {
+			t.Fatalf("Deviation %q (%s) not found in the README resulting table %q", d.readme, d.reason, want)
+		}
+		want = strings.Replace(want, d.readme, d.golden, 1)
+	}
+	if gotReport = strings.TrimSuffix(gotReport, "End of task\n"); gotReport != want {
+		t.Errorf("Final report mismatch with README.md:\n%s", diffLines(want, gotReport))
+	}
+}
+
+// readmeBlock returns the lines of the first code block after the title in the README.
+// The last block of the README is not closed, it ends with the file.
+func readmeBlock(t *testing.T, readme, title string) string {
+	t.Helper()
+	_, section, ok := strings.Cut(readme, title)
+	if !ok {
+		t.Fatalf("No %s in README.md", title)
+	}
+	_, block, ok := strings.Cut(section, "```")
+	if !ok {
+		t.Fatalf("No code block after %s in README.md", title)
+	}
+	block, _, _ = strings.Cut(block, "```")
+	return strings.TrimSpace(block) + "\n"
+}
+
+// runGolden runs the case in dir and returns the output of the CLI.
+func runGolden(t *testing.T, dir string) []byte {
+	t.Helper()
+	cfg, err := config.Load(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	f, err := os.Open(filepath.Join(dir, "events"))
+	if err != nil {
+		t.Fatalf("Failed to open events: %v", err)
+	}
+	defer f.Close()
+
+	var raceOpts []RaceOption
+	reg, err := registry.Load(filepath.Join(dir, "start_list.csv"))
+	switch {
+	case err == nil:
+		raceOpts = append(raceOpts, WithRegistry(reg))
+	case !errors.Is(err, os.ErrNotExist):
+		t.Fatalf("Failed to load start list: %v", err)
+	}
+
+	// The output log and the report share the buffer the way they share the standard output
+	var out bytes.Buffer
+	task := NewTask(cfg, scannerEvent.NewScanner(f),
+		WithEventSink(sink.NewEventWriter(&out)),
+		WithReportSink(sink.NewReportWriter(&out, reporting.FormatText, cfg)),
+		WithRaceOptions(raceOpts...))
+	if err := task.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	return out.Bytes()
+}
+
+// diffLines reports the first differing line of want and got with its line number.
+func diffLines(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, w, g)
+		}
+	}
+	return ""
+}
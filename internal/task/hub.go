@@ -0,0 +1,118 @@
+package task
+
+import (
+	"sync"
+
+	"github.com/Valery223/biathlon-test/internal/domain"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+)
+
+// MessageKind is the kind of a Message published by the Hub.
+type MessageKind string
+
+const (
+	// MessageEvent is published for every event of the output log.
+	MessageEvent MessageKind = "event"
+	// MessageFinished is published instead of MessageEvent when a competitor has finished.
+	MessageFinished MessageKind = "finished"
+	// MessageDisqualified is published instead of MessageEvent when a competitor is disqualified.
+	MessageDisqualified MessageKind = "disqualified"
+	// MessageStandings is published when the order of the standings has changed.
+	MessageStandings MessageKind = "standings"
+)
+
+// Message is a notification about a change of the race.
+type Message struct {
+	Kind MessageKind
+	// Seq is the index of the event in the output log, set for event messages.
+	Seq   int
+	Event domain.Event
+	// Standings is set for MessageStandings.
+	Standings []reporting.Standing
+}
+
+// newEventMessage creates a message for an event of the output log.
+func newEventMessage(seq int, e domain.Event) Message {
+	kind := MessageEvent
+	switch e.ID {
+	case domain.EventCompetitorFinished:
+		kind = MessageFinished
+	case domain.EventCompetitorDisqualified:
+		kind = MessageDisqualified
+	}
+	return Message{Kind: kind, Seq: seq, Event: e}
+}
+
+// Hub fans messages out to many subscribers.
+// Publishing never blocks: a subscriber whose buffer is full is considered too slow
+// and is unsubscribed, its channel is closed.
+type Hub struct {
+	mu          sync.Mutex
+	buffer      int
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives messages published by the Hub.
+type Subscription struct {
+	hub *Hub
+	ch  chan Message
+}
+
+// NewHub creates a Hub with the given buffer size for every subscriber.
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		buffer:      buffer,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber.
+// The subscriber must call Close when it no longer reads messages.
+func (h *Hub) Subscribe() *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := &Subscription{hub: h, ch: make(chan Message, h.buffer)}
+	h.subscribers[s] = struct{}{}
+	return s
+}
+
+// Subscribers returns the number of current subscribers.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+// Publish sends the message to all subscribers without blocking.
+// Slow subscribers are dropped.
+func (h *Hub) Publish(m Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subscribers {
+		select {
+		case s.ch <- m:
+		default:
+			delete(h.subscribers, s)
+			close(s.ch)
+		}
+	}
+}
+
+// C returns the channel of messages.
+// The channel is closed when the subscription is closed or dropped as too slow.
+func (s *Subscription) C() <-chan Message {
+	return s.ch
+}
+
+// Close unsubscribes from the Hub.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if _, ok := s.hub.subscribers[s]; ok {
+		delete(s.hub.subscribers, s)
+		close(s.ch)
+	}
+}
@@ -0,0 +1,151 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+)
+
+func TestHub(t *testing.T) {
+	hub := NewHub(2)
+	fast := hub.Subscribe()
+	slow := hub.Subscribe()
+	defer fast.Close()
+
+	for seq := range 3 {
+		hub.Publish(newEventMessage(seq, domain.Event{ID: domain.EventCompetitorRegistered, CompetitorID: seq}))
+		if seq < 2 {
+			// The fast subscriber keeps up with the publisher
+			<-fast.C()
+		}
+	}
+
+	// The slow subscriber has not read anything, the third message overflowed its buffer
+	received := 0
+	for range slow.C() {
+		received++
+	}
+	if received != 2 {
+		t.Errorf("slow subscriber: got %d messages before being dropped, want 2", received)
+	}
+
+	m, ok := <-fast.C()
+	if !ok || m.Seq != 2 {
+		t.Errorf("fast subscriber: got %+v, %v, want message 2", m, ok)
+	}
+
+	// Closing a dropped subscription is a no-op
+	slow.Close()
+}
+
+func TestNewEventMessage(t *testing.T) {
+	testCases := []struct {
+		id   domain.EventID
+		want MessageKind
+	}{
+		{id: domain.EventCompetitorStarted, want: MessageEvent},
+		{id: domain.EventCompetitorFinished, want: MessageFinished},
+		{id: domain.EventCompetitorDisqualified, want: MessageDisqualified},
+	}
+	for _, tc := range testCases {
+		if got := newEventMessage(0, domain.Event{ID: tc.id}).Kind; got != tc.want {
+			t.Errorf("newEventMessage(event %d).Kind: got %q, want %q", tc.id, got, tc.want)
+		}
+	}
+}
+
+func TestRace_PublishesStandings(t *testing.T) {
+	cfg := &config.Config{Laps: 2, LapLength: 3000, StartDelta: 30 * time.Second}
+	start, _ := time.Parse("15:04:05.000", "10:00:00.000")
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	// received returns the kinds of the messages published so far
+	received := func(sub *Subscription) []MessageKind {
+		var kinds []MessageKind
+		for {
+			select {
+			case m := <-sub.C():
+				kinds = append(kinds, m.Kind)
+			default:
+				return kinds
+			}
+		}
+	}
+	newRace := func(t *testing.T, events []domain.Event) (*Race, *Subscription) {
+		race := NewRace(cfg)
+		sub := race.Subscribe()
+		t.Cleanup(sub.Close)
+		for i := range events {
+			if _, err := race.Process(&events[i]); err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+		}
+		received(sub)
+		return race, sub
+	}
+	registered := []domain.Event{
+		{Time: at(-time.Hour), ID: domain.EventCompetitorRegistered, CompetitorID: 1},
+		{Time: at(-time.Hour), ID: domain.EventCompetitorRegistered, CompetitorID: 2},
+		{Time: at(-time.Hour), ID: domain.EventStartTimeSet, CompetitorID: 1, Comments: "10:00:00.000"},
+		{Time: at(-time.Hour), ID: domain.EventStartTimeSet, CompetitorID: 2, Comments: "10:00:30.000"},
+		{Time: at(time.Second), ID: domain.EventCompetitorStarted, CompetitorID: 1},
+	}
+
+	t.Run("NotFinished", func(t *testing.T) {
+		race, sub := newRace(t, append(registered,
+			domain.Event{Time: at(31 * time.Second), ID: domain.EventCompetitorStarted, CompetitorID: 2},
+			domain.Event{Time: at(10 * time.Minute), ID: domain.EventCompetitorEndedMainLap, CompetitorID: 1},
+		))
+
+		// Competitor 1 leads and can't continue before the next lap ends
+		e := domain.Event{Time: at(11 * time.Minute), ID: domain.EventCompetitorCanNotContinue, CompetitorID: 1, Comments: "Broken ski"}
+		if _, err := race.Process(&e); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		got := received(sub)
+		if len(got) != 2 || got[0] != MessageEvent || got[1] != MessageStandings {
+			t.Errorf("Messages: got %v, want [event standings]", got)
+		}
+	})
+
+	t.Run("Disqualified", func(t *testing.T) {
+		race, sub := newRace(t, registered)
+
+		// The race clock disqualifies competitor 2 before the firing range event is handled
+		e := domain.Event{Time: at(5 * time.Minute), ID: domain.EventCompetitorOnFiringRange, CompetitorID: 1, Comments: "1"}
+		if _, err := race.Process(&e); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		got := received(sub)
+		if len(got) != 3 || got[0] != MessageDisqualified || got[1] != MessageEvent || got[2] != MessageStandings {
+			t.Errorf("Messages: got %v, want [disqualified event standings]", got)
+		}
+	})
+
+	t.Run("LateSubscriber", func(t *testing.T) {
+		// Standings are not built without subscribers, a late subscriber gets them on the next lap
+		// even though the order of competitors has not changed
+		race := NewRace(cfg)
+		for _, e := range append(registered,
+			domain.Event{Time: at(31 * time.Second), ID: domain.EventCompetitorStarted, CompetitorID: 2},
+			domain.Event{Time: at(10 * time.Minute), ID: domain.EventCompetitorEndedMainLap, CompetitorID: 1},
+		) {
+			if _, err := race.Process(&e); err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+		}
+		sub := race.Subscribe()
+		t.Cleanup(sub.Close)
+
+		e := domain.Event{Time: at(11 * time.Minute), ID: domain.EventCompetitorEndedMainLap, CompetitorID: 2}
+		if _, err := race.Process(&e); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		got := received(sub)
+		if len(got) != 2 || got[0] != MessageEvent || got[1] != MessageStandings {
+			t.Errorf("Messages: got %v, want [event standings]", got)
+		}
+	})
+}
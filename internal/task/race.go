@@ -0,0 +1,197 @@
+package task
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+	"github.com/Valery223/biathlon-test/internal/eventproccesor"
+	"github.com/Valery223/biathlon-test/internal/registry"
+	"github.com/Valery223/biathlon-test/internal/reporting"
+)
+
+// Race holds the live state of a race: the competitors and the output log.
+// It is safe for concurrent use, so the state can be read while events are processed.
+type Race struct {
+	mu          sync.RWMutex
+	cfg         *config.Config
+	competitors map[int]*domain.Competitor
+	processor   *eventproccesor.Processor
+	log         []domain.Event // Output log: incoming and outgoing events in order
+	hub         *Hub
+	order       []int // Competitor IDs in the order of the last published standings
+}
+
+// subscriberBuffer is the number of messages buffered for every subscriber of a Race.
+const subscriberBuffer = 256
+
+// RaceOption configures optional parameters of a Race.
+type RaceOption func(*Race)
+
+// WithRegistry sets the start list of the race.
+// Events for competitors missing from the start list are invalid.
+func WithRegistry(reg *registry.Registry) RaceOption {
+	return func(r *Race) {
+		r.processor.UseRegistry(reg)
+	}
+}
+
+// NewRace creates an empty Race for the given configuration.
+func NewRace(cfg *config.Config, opts ...RaceOption) *Race {
+	competitors := make(map[int]*domain.Competitor)
+	r := &Race{
+		cfg:         cfg,
+		competitors: competitors,
+		processor:   eventproccesor.NewProcessor(cfg, competitors),
+		hub:         NewHub(subscriberBuffer),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Subscribe returns a subscription to the events of the output log
+// and to changes of the standings, see Hub.
+func (r *Race) Subscribe() *Subscription {
+	return r.hub.Subscribe()
+}
+
+// Process validates and handles a single incoming event.
+// It returns the events appended to the output log: events generated by the race clock,
+// the incoming event itself and the outgoing events it caused.
+// An invalid event is not handled and the returned error wraps eventproccesor.ErrInvalidEvent.
+// The standings are published when a competitor finishes, can't continue or is disqualified,
+// and after a completed lap if the order of competitors has changed.
+func (r *Race) Process(e *domain.Event) ([]domain.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, err := r.processor.Validate(e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Events generated by the race clock happened before the current event
+	events := r.processor.Advance(e.Time)
+	events = append(events, *e)
+
+	outgoing, err := r.processor.Handle(t)
+	if err != nil {
+		// Disqualifications generated by the race clock are logged even if the event fails
+		events = events[:len(events)-1]
+	} else {
+		events = append(events, outgoing...)
+	}
+
+	r.appendLog(events)
+	switch {
+	case containsEvent(events, domain.EventCompetitorCanNotContinue, domain.EventCompetitorDisqualified, domain.EventCompetitorFinished):
+		// The status of a competitor has changed
+		r.publishStandings(true)
+	case containsEvent(events, domain.EventCompetitorEndedMainLap):
+		r.publishStandings(false)
+	}
+	return events, err
+}
+
+// containsEvent reports whether any of the events has one of the IDs.
+func containsEvent(events []domain.Event, ids ...domain.EventID) bool {
+	return slices.ContainsFunc(events, func(e domain.Event) bool {
+		return slices.Contains(ids, e.ID)
+	})
+}
+
+// appendLog appends the events to the output log and publishes them.
+func (r *Race) appendLog(events []domain.Event) {
+	for _, e := range events {
+		r.hub.Publish(newEventMessage(len(r.log), e))
+		r.log = append(r.log, e)
+	}
+}
+
+// publishStandings publishes the current standings.
+// Unless always is set, they are published only if the order of competitors has changed.
+func (r *Race) publishStandings(always bool) {
+	// Building the standings calculates and sorts the reports of all competitors, skip it if nobody listens.
+	// The order is forgotten so that the next change of standings is published to a new subscriber.
+	if r.hub.Subscribers() == 0 {
+		r.order = nil
+		return
+	}
+	standings := reporting.BuildStandings(r.reports())
+	order := make([]int, 0, len(standings))
+	for _, s := range standings {
+		order = append(order, s.Report.CompetitorID)
+	}
+	if !always && slices.Equal(order, r.order) {
+		return
+	}
+	r.order = order
+	r.hub.Publish(Message{Kind: MessageStandings, Standings: standings})
+}
+
+// Close is called at the end of the event stream.
+// It returns the disqualification events for competitors who have not started
+// and publishes the final standings.
+func (r *Race) Close() []domain.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := r.processor.Flush()
+	r.appendLog(events)
+	r.publishStandings(true)
+	return events
+}
+
+// Events returns the events of the output log starting from the index since.
+func (r *Race) Events(since int) []domain.Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if since < 0 {
+		since = 0
+	}
+	if since >= len(r.log) {
+		return nil
+	}
+	return slices.Clone(r.log[since:])
+}
+
+// Reports returns the current reports of all competitors ordered by competitor ID.
+func (r *Race) Reports() []reporting.Report {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.reports()
+}
+
+// reports returns the current reports, the caller must hold the lock.
+func (r *Race) reports() []reporting.Report {
+	reports := make([]reporting.Report, 0, len(r.competitors))
+	for _, competitor := range r.competitors {
+		reports = append(reports, reporting.CalculateReport(*competitor, r.cfg))
+	}
+	slices.SortFunc(reports, func(a, b reporting.Report) int {
+		return a.CompetitorID - b.CompetitorID
+	})
+	return reports
+}
+
+// Report returns the current report of the competitor with the given ID.
+func (r *Race) Report(id int) (reporting.Report, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	competitor, ok := r.competitors[id]
+	if !ok {
+		return reporting.Report{}, false
+	}
+	return reporting.CalculateReport(*competitor, r.cfg), true
+}
+
+// Standings returns the current standings of the race.
+func (r *Race) Standings() []reporting.Standing {
+	return reporting.BuildStandings(r.Reports())
+}
@@ -1,59 +1,150 @@
 package task
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
 
 	"github.com/Valery223/biathlon-test/internal/config"
 	"github.com/Valery223/biathlon-test/internal/domain"
 	"github.com/Valery223/biathlon-test/internal/eventproccesor"
 	"github.com/Valery223/biathlon-test/internal/reporting"
+	"github.com/Valery223/biathlon-test/internal/sink"
 )
 
 type ScannerEvent interface {
 	Scan(*domain.Event) error
 }
 
+// LineScanner is a ScannerEvent which knows the input line of the last scanned event.
+// Errors of invalid events refer to the line, other scanners are referred to by the event number.
+type LineScanner interface {
+	ScannerEvent
+	Line() int
+}
+
 type Task struct {
 	cfg     *config.Config
 	scanner ScannerEvent
+	// events receives the output log
+	events sink.EventSink
+	// reports receives the intermediate and final standings
+	reports sink.ReportSink
+	// validationMode defines how invalid events are treated
+	validationMode ValidationMode
+	// liveStandings enables intermediate standings after every lap
+	liveStandings bool
+	raceOpts      []RaceOption
+	race          *Race
+}
+
+// ValidationMode defines how the Task treats events which violate the order of the race.
+type ValidationMode int
+
+const (
+	// ValidationStrict stops the task on the first invalid event.
+	ValidationStrict ValidationMode = iota
+	// ValidationLenient logs a warning and skips invalid events.
+	ValidationLenient
+)
+
+// ParseValidationMode converts a string ("strict" or "lenient") into a ValidationMode.
+func ParseValidationMode(s string) (ValidationMode, error) {
+	switch s {
+	case "strict":
+		return ValidationStrict, nil
+	case "lenient":
+		return ValidationLenient, nil
+	default:
+		return 0, fmt.Errorf("unknown validation mode %q", s)
+	}
+}
+
+// Option configures optional parameters of a Task.
+type Option func(*Task)
+
+// WithEventSink sets the destination of the output log. The default is sink.Stdout().
+func WithEventSink(s sink.EventSink) Option {
+	return func(t *Task) {
+		t.events = s
+	}
 }
 
-func NewTask(cfg *config.Config, scanner ScannerEvent) *Task {
-	return &Task{
+// WithReportSink sets the destination of the standings.
+// The default writes the final report to the standard output in the text format.
+func WithReportSink(s sink.ReportSink) Option {
+	return func(t *Task) {
+		t.reports = s
+	}
+}
+
+// WithValidationMode sets how invalid events are treated. The default is ValidationStrict.
+func WithValidationMode(mode ValidationMode) Option {
+	return func(t *Task) {
+		t.validationMode = mode
+	}
+}
+
+// WithRaceOptions sets the options of the Race processed by the Task.
+func WithRaceOptions(opts ...RaceOption) Option {
+	return func(t *Task) {
+		t.raceOpts = append(t.raceOpts, opts...)
+	}
+}
+
+// WithLiveStandings enables writing intermediate standings to the report sink after every completed lap.
+func WithLiveStandings(enabled bool) Option {
+	return func(t *Task) {
+		t.liveStandings = enabled
+	}
+}
+
+func NewTask(cfg *config.Config, scanner ScannerEvent, opts ...Option) *Task {
+	t := &Task{
 		cfg:     cfg,
 		scanner: scanner,
+		events:  sink.Stdout(),
+		reports: sink.NewReportWriter(os.Stdout, reporting.FormatText, cfg),
+	}
+	for _, opt := range opts {
+		opt(t)
 	}
+	t.race = NewRace(cfg, t.raceOpts...)
+	return t
+}
+
+// Race returns the live state of the race processed by the Task.
+func (t Task) Race() *Race {
+	return t.race
 }
 
 // Execute runs the main simulation loop.
-// It continuously scans for events, handles them, and once all events are processed,
-// it prints a report for each competitor.
+// It continuously scans for events, handles them, and once all events are processed
+// or the race is closed by the sentinel event, it writes the standings to the report sink.
 // It returns an error if a critical issue occurs during event scanning or processing.
 func (t Task) Execute() error {
-	mapCompetitors := make(map[int]*domain.Competitor)
-
-	err := t.processAllEvents(mapCompetitors)
+	err := t.processAllEvents()
 	if err != nil {
 		return fmt.Errorf("error processing events: %w", err)
 	}
 
-	// Check for competitors who have not started
-	t.checkNotStartedCompetitors(mapCompetitors)
-
-	fmt.Println("Final reports")
-	for _, competitor := range mapCompetitors {
-		report := reporting.CalculateReport(*competitor, t.cfg)
-		fmt.Println(report)
+	// Disqualify competitors who have not started before the end of the stream
+	err = t.showEvents(t.race.Close())
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("End of task")
+	err = t.reports.WriteFinal(t.race.Standings())
+	if err != nil {
+		return fmt.Errorf("error writing report: %w", err)
+	}
 	return nil
 }
 
-func (t Task) processAllEvents(mapCompetitors map[int]*domain.Competitor) error {
-	for {
+func (t Task) processAllEvents() error {
+	for n := 1; ; n++ {
 		event := &domain.Event{}
 		err := t.scanner.Scan(event)
 		if err != nil {
@@ -65,35 +156,52 @@ func (t Task) processAllEvents(mapCompetitors map[int]*domain.Competitor) error
 
 		}
 
-		err = t.handleAndShowEvent(event, mapCompetitors)
-
+		events, err := t.race.Process(event)
+		if err := t.showEvents(events); err != nil {
+			return err
+		}
+		if errors.Is(err, eventproccesor.ErrInvalidEvent) {
+			if t.validationMode == ValidationLenient {
+				log.Printf("warning: %s: %v, event skipped", t.position(n), err)
+				continue
+			}
+			return fmt.Errorf("%s: %w", t.position(n), err)
+		}
 		if err != nil {
-			return fmt.Errorf("error handling event: %w", err)
+			return fmt.Errorf("%s: error handling event: %w", t.position(n), err)
+		}
+
+		if event.ID == domain.EventRaceClosed {
+			log.Println("Race closed")
+			break
 		}
 
+		if t.liveStandings && event.ID == domain.EventCompetitorEndedMainLap {
+			err = t.reports.WriteIntermediate(event.Time, t.race.Standings())
+			if err != nil {
+				return fmt.Errorf("error writing intermediate standings: %w", err)
+			}
+		}
 	}
 
 	return nil
 }
 
-func (t Task) handleAndShowEvent(event *domain.Event, mapCompetitors map[int]*domain.Competitor) error {
-	fmt.Println(event.Format())
-	err := eventproccesor.HandleEvent(event, mapCompetitors, t.cfg.Laps)
-
-	return err
+// position describes the position of the n-th scanned event in the input for error messages.
+func (t Task) position(n int) string {
+	if s, ok := t.scanner.(LineScanner); ok && s.Line() > 0 {
+		return fmt.Sprintf("line %d", s.Line())
+	}
+	return fmt.Sprintf("event %d", n)
 }
 
-func (t Task) checkNotStartedCompetitors(mapCompetitors map[int]*domain.Competitor) {
-	for _, competitor := range mapCompetitors {
-		if competitor.ActualStart.Sub(competitor.ScheduledStart) > t.cfg.StartDelta {
-			competitor.Status = domain.StatusNotStarted
-			event := &domain.Event{
-				Time:         competitor.ScheduledStart.Add(t.cfg.StartDelta),
-				ID:           32,
-				CompetitorID: competitor.ID,
-				Comments:     "",
-			}
-			fmt.Println(event.Format())
+// showEvents writes the events to the output log.
+func (t Task) showEvents(events []domain.Event) error {
+	for _, e := range events {
+		err := t.events.WriteEvent(e)
+		if err != nil {
+			return fmt.Errorf("error writing event: %w", err)
 		}
 	}
+	return nil
 }
@@ -0,0 +1,102 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Valery223/biathlon-test/internal/config"
+	"github.com/Valery223/biathlon-test/internal/domain"
+	scannerEvent "github.com/Valery223/biathlon-test/internal/scanner"
+	"github.com/Valery223/biathlon-test/internal/sink"
+)
+
+func TestTask_Execute(t *testing.T) {
+	cfg := &config.Config{Laps: 1, LapLength: 3000, PenaltyLength: 150, FiringLines: 1, StartDelta: 30 * time.Second}
+	input := strings.Join([]string{
+		"[09:00:00.000] 1 1",
+		"[09:00:00.000] 1 2",
+		"[09:01:00.000] 2 1 10:00:00.000",
+		"[09:01:00.000] 2 2 10:01:00.000",
+		"[10:00:01.000] 4 1",
+		"[10:10:00.000] 10 1",
+	}, "\n")
+
+	memory := sink.NewMemory()
+	task := NewTask(cfg, scannerEvent.NewScanner(strings.NewReader(input)),
+		WithEventSink(memory), WithReportSink(memory), WithLiveStandings(true))
+	if err := task.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	var got []domain.EventID
+	for _, e := range memory.Events() {
+		got = append(got, e.ID)
+	}
+	// Competitor 2 never starts and is disqualified by the race clock before the lap of competitor 1 ends
+	want := []domain.EventID{1, 1, 2, 2, 4, domain.EventCompetitorDisqualified, 10, domain.EventCompetitorFinished}
+	if len(got) != len(want) {
+		t.Fatalf("Output log mismatch: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Output log mismatch: got %v, want %v", got, want)
+		}
+	}
+
+	if n := len(memory.Intermediate()); n != 1 {
+		t.Errorf("Intermediate standings: got %d, want 1", n)
+	}
+
+	final := memory.Final()
+	if len(final) != 2 {
+		t.Fatalf("Final standings: got %d, want 2", len(final))
+	}
+	if final[0].Rank != 1 || final[0].Report.CompetitorID != 1 || final[0].Report.Status != domain.StatusFinished {
+		t.Errorf("Unexpected leader: %+v", final[0])
+	}
+	if final[1].Report.CompetitorID != 2 || final[1].Report.Status != domain.StatusNotStarted {
+		t.Errorf("Unexpected second standing: %+v", final[1])
+	}
+}
+
+// scanOnly hides the line tracking of the wrapped scanner.
+type scanOnly struct {
+	ScannerEvent
+}
+
+func TestTask_InvalidEventPosition(t *testing.T) {
+	cfg := &config.Config{Laps: 1, LapLength: 3000, PenaltyLength: 150, FiringLines: 1, StartDelta: 30 * time.Second}
+	input := strings.Join([]string{
+		"[09:00:00.000] 1 1",
+		"[09:01:00.000] 2 1 10:00:00.000",
+		"[09:02:00.000] 5 1 1", // Not started yet
+	}, "\n")
+
+	tests := []struct {
+		name    string
+		scanner func() ScannerEvent
+		want    string
+	}{
+		{
+			name:    "Line",
+			scanner: func() ScannerEvent { return scannerEvent.NewScanner(strings.NewReader(input)) },
+			want:    "line 3: invalid event",
+		},
+		{
+			name:    "EventNumber",
+			scanner: func() ScannerEvent { return scanOnly{scannerEvent.NewScanner(strings.NewReader(input))} },
+			want:    "event 3: invalid event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memory := sink.NewMemory()
+			err := NewTask(cfg, tt.scanner(), WithEventSink(memory), WithReportSink(memory)).Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute error: got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}